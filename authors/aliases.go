@@ -0,0 +1,107 @@
+package authors
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// AliasOptions - which generated variants of an author's name are added to the aliases
+type AliasOptions struct {
+	// Initials adds the name with the given names shortened to initials, as in "M. Wolf"
+	Initials bool
+	// StripDiacritics adds every alias with its accents removed, as in "Jose Lopez" for "José López"
+	StripDiacritics bool
+}
+
+// normaliseAlias puts an alias in Unicode NFC form and collapses its whitespace
+func normaliseAlias(alias string) string {
+	return strings.Join(strings.Fields(norm.NFC.String(alias)), " ")
+}
+
+// completeAliases normalises the aliases of an author, adds the canonical name and the generated
+// variants asked for and removes the duplicates
+func completeAliases(aliasList []string, canonicalName string, opts AliasOptions) []string {
+	all := append(append([]string{}, aliasList...), canonicalName)
+	if opts.Initials {
+		if initials := initialsForm(normaliseAlias(canonicalName)); initials != "" {
+			all = append(all, initials)
+		}
+	}
+	if opts.StripDiacritics {
+		for _, alias := range all {
+			all = append(all, stripDiacritics(normaliseAlias(alias)))
+		}
+	}
+	return dedupeAliases(all)
+}
+
+// dedupeAliases normalises the aliases and removes those which only differ by case, keeping the
+// best-cased form in the position of the first one. Empty aliases are dropped.
+func dedupeAliases(aliasList []string) []string {
+	deduped := []string{}
+	positions := make(map[string]int)
+	for _, alias := range aliasList {
+		alias = normaliseAlias(alias)
+		if alias == "" {
+			continue
+		}
+		key := strings.ToLower(alias)
+		if i, ok := positions[key]; ok {
+			if caseRank(alias) > caseRank(deduped[i]) {
+				deduped[i] = alias
+			}
+			continue
+		}
+		positions[key] = len(deduped)
+		deduped = append(deduped, alias)
+	}
+	return deduped
+}
+
+// caseRank prefers mixed case aliases such as "Martin Wolf" to "MARTIN WOLF", and both to "martin wolf"
+func caseRank(alias string) int {
+	switch {
+	case alias == strings.ToLower(alias):
+		return 0
+	case alias == strings.ToUpper(alias):
+		return 1
+	}
+	return 2
+}
+
+// initialsForm shortens the given names to initials, as in "J. P. Rathbone" for "John Paul Rathbone".
+// Names which can't be split into given and family names have no initials form.
+func initialsForm(name string) string {
+	n := parseName(name)
+	if n.GivenName == "" || n.FamilyName == "" {
+		return ""
+	}
+	var initials []string
+	for _, given := range strings.Fields(n.GivenName) {
+		initials = append(initials, string(unicode.ToUpper([]rune(given)[0]))+".")
+	}
+	return strings.Join(initials, " ") + " " + n.FamilyName
+}
+
+func stripDiacritics(alias string) string {
+	stripped := []rune{}
+	for _, r := range norm.NFD.String(alias) {
+		if !unicode.Is(unicode.Mn, r) {
+			stripped = append(stripped, r)
+		}
+	}
+	return norm.NFC.String(string(stripped))
+}
+
+// splitCuratedList splits a list cell of the curated spreadsheet, which holds one value per line or separated by semicolons
+func splitCuratedList(cell string) []string {
+	var values []string
+	for _, v := range strings.FieldsFunc(cell, func(r rune) bool { return r == ';' || r == '\n' || r == '\r' }) {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
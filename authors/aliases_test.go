@@ -0,0 +1,51 @@
+package authors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDedupeAliases(t *testing.T) {
+	tests := []struct {
+		name     string
+		aliases  []string
+		expected []string
+	}{
+		{"Whitespace is collapsed", []string{"Martin  Wolf", " Martin Wolf ", "Martin\tWolf"}, []string{"Martin Wolf"}},
+		{"Case insensitive keeping the best cased form", []string{"martin wolf", "MARTIN WOLF", "Martin Wolf", "Wolf"}, []string{"Martin Wolf", "Wolf"}},
+		{"Upper case is better than lower case", []string{"ft", "FT"}, []string{"FT"}},
+		{"Unicode normal form", []string{"Jose\u0301 Lo\u0301pez", "José López"}, []string{"José López"}},
+		{"Empty aliases are dropped", []string{"", "  ", "Bob"}, []string{"Bob"}},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, dedupeAliases(test.aliases), test.name)
+	}
+}
+
+func TestCompleteAliases(t *testing.T) {
+	tests := []struct {
+		name      string
+		aliases   []string
+		canonical string
+		opts      AliasOptions
+		expected  []string
+	}{
+		{"No variants", []string{"M Wolf"}, "Martin Wolf", AliasOptions{}, []string{"M Wolf", "Martin Wolf"}},
+		{"Initials", []string{}, "John Paul Rathbone", AliasOptions{Initials: true}, []string{"John Paul Rathbone", "J. P. Rathbone"}},
+		{"Initials keep the family name particles", []string{}, "Ludwig von Mises", AliasOptions{Initials: true}, []string{"Ludwig von Mises", "L. von Mises"}},
+		{"No initials for a single name", []string{}, "Cher", AliasOptions{Initials: true}, []string{"Cher"}},
+		{"Diacritics stripped", []string{"Señor López"}, "José López", AliasOptions{StripDiacritics: true}, []string{"Señor López", "José López", "Senor Lopez", "Jose Lopez"}},
+		{"Both", []string{}, "Zoë Brown", AliasOptions{Initials: true, StripDiacritics: true}, []string{"Zoë Brown", "Z. Brown", "Zoe Brown"}},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, completeAliases(test.aliases, test.canonical, test.opts), test.name)
+	}
+}
+
+func TestSplitCuratedList(t *testing.T) {
+	assert.Equal(t, []string{"M. Wolf", "Martin H. Wolf", "Wolfie"}, splitCuratedList("M. Wolf; Martin H. Wolf\r\nWolfie; "))
+	assert.Empty(t, splitCuratedList(""))
+}
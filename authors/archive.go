@@ -0,0 +1,232 @@
+package authors
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const archiveIndex = "index.json"
+
+// recorder is implemented by the HTTP clients which archive the responses fetched during a reload
+type recorder interface {
+	startRecording(startedAt time.Time)
+	finishRecording() (string, error)
+}
+
+// recordedResponse is an entry of the index of an archive
+type recordedResponse struct {
+	Method      string `json:"method"`
+	URL         string `json:"url"`
+	StatusCode  int    `json:"statusCode"`
+	ContentType string `json:"contentType,omitempty"`
+	File        string `json:"file"`
+}
+
+// RecordingClient - an HTTP client which writes every raw TME page and Bertha payload fetched during a reload
+// to a timestamped archive in a directory
+type RecordingClient struct {
+	sync.Mutex
+	client    httpClient
+	dir       string
+	recording bool
+	startedAt time.Time
+	index     []recordedResponse
+	bodies    map[string][]byte
+}
+
+// NewRecordingClient - record the responses of a client into archives written to a directory
+func NewRecordingClient(client httpClient, dir string) *RecordingClient {
+	return &RecordingClient{client: client, dir: dir}
+}
+
+// Do sends a request, recording its response while a reload is in progress
+func (c *RecordingClient) Do(req *http.Request) (*http.Response, error) {
+	resp, err := c.client.Do(req)
+	if err != nil || resp == nil || resp.Body == nil || !c.isRecording() {
+		return resp, err
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	c.Lock()
+	defer c.Unlock()
+	if c.recording {
+		r := recordedResponse{Method: req.Method, URL: requestKey(req), StatusCode: resp.StatusCode, ContentType: resp.Header.Get("Content-Type"),
+			File: fmt.Sprintf("responses/%05d", len(c.index)+1)}
+		c.index = append(c.index, r)
+		c.bodies[r.File] = body
+	}
+	return resp, nil
+}
+
+func (c *RecordingClient) isRecording() bool {
+	c.Lock()
+	defer c.Unlock()
+	return c.recording
+}
+
+func (c *RecordingClient) startRecording(startedAt time.Time) {
+	c.Lock()
+	defer c.Unlock()
+	c.recording = true
+	c.startedAt = startedAt
+	c.index = []recordedResponse{}
+	c.bodies = make(map[string][]byte)
+}
+
+// finishRecording writes the responses recorded since the reload started to an archive and returns its file name
+func (c *RecordingClient) finishRecording() (string, error) {
+	c.Lock()
+	defer c.Unlock()
+	c.recording = false
+	fileName := filepath.Join(c.dir, "reload-"+c.startedAt.UTC().Format("20060102T150405Z")+".tar.gz")
+	if err := writeArchive(fileName, c.index, c.bodies); err != nil {
+		return "", err
+	}
+	c.index, c.bodies = nil, nil
+	return fileName, nil
+}
+
+func writeArchive(fileName string, index []recordedResponse, bodies map[string][]byte) error {
+	f, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	marshalledIndex, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeArchiveFile(tw, archiveIndex, marshalledIndex); err != nil {
+		return err
+	}
+	for _, r := range index {
+		if err := writeArchiveFile(tw, r.File, bodies[r.File]); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func writeArchiveFile(tw *tar.Writer, name string, contents []byte) error {
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(contents)), ModTime: time.Now()}); err != nil {
+		return err
+	}
+	_, err := tw.Write(contents)
+	return err
+}
+
+// ReplayClient - an HTTP client answering the requests of a reload with the responses of an archive
+type ReplayClient struct {
+	responses map[string]recordedResponse
+	bodies    map[string][]byte
+}
+
+// NewReplayClient - read an archive written by a recording client
+func NewReplayClient(fileName string) (*ReplayClient, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("Archive %v is not gzipped: %v", fileName, err)
+	}
+	tr := tar.NewReader(gz)
+
+	c := &ReplayClient{responses: make(map[string]recordedResponse), bodies: make(map[string][]byte)}
+	var index []recordedResponse
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Archive %v is not a valid tar: %v", fileName, err)
+		}
+		contents, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		if header.Name == archiveIndex {
+			if err := json.Unmarshal(contents, &index); err != nil {
+				return nil, fmt.Errorf("Index of archive %v is not valid JSON: %v", fileName, err)
+			}
+			continue
+		}
+		c.bodies[header.Name] = contents
+	}
+	if index == nil {
+		return nil, fmt.Errorf("Archive %v has no %v", fileName, archiveIndex)
+	}
+	for _, r := range index {
+		c.responses[r.Method+" "+r.URL] = r
+	}
+	return c, nil
+}
+
+// Do answers a request with the recorded response of the same request. Later responses to a request win.
+func (c *ReplayClient) Do(req *http.Request) (*http.Response, error) {
+	r, ok := c.responses[req.Method+" "+requestKey(req)]
+	if !ok {
+		return nil, fmt.Errorf("No recorded response for %v %v", req.Method, requestKey(req))
+	}
+	resp := &http.Response{
+		Status:     fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode: r.StatusCode,
+		Header:     http.Header{},
+		Body:       ioutil.NopCloser(bytes.NewReader(c.bodies[r.File])),
+		Request:    req,
+	}
+	if r.ContentType != "" {
+		resp.Header.Set("Content-Type", r.ContentType)
+	}
+	return resp, nil
+}
+
+// requestKey is the URL of a request without its credentials
+func requestKey(req *http.Request) string {
+	u := *req.URL
+	u.User = nil
+	return u.String()
+}
+
+// recordReload starts recording the responses of a reload when the HTTP client can, and returns what finishes it
+func recordReload(client httpClient, startedAt time.Time) func() {
+	r, ok := client.(recorder)
+	if !ok {
+		return func() {}
+	}
+	r.startRecording(startedAt)
+	return func() {
+		fileName, err := r.finishRecording()
+		if err != nil {
+			log.Errorf("Error while archiving the responses of the reload: [%v]", err.Error())
+			return
+		}
+		log.Infof("Archived the responses of the reload to %v.", fileName)
+	}
+}
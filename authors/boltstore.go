@@ -0,0 +1,336 @@
+package authors
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	bolt "go.etcd.io/bbolt"
+)
+
+// BoltAuthorStore - an AuthorStore in a bolt file. Each generation is a nested bucket of the generations bucket.
+// Views run alongside each other, updates one after the other, and compactions while no update runs.
+type BoltAuthorStore struct {
+	sync.RWMutex
+	writing  sync.Mutex
+	fileName string
+	db       *bolt.DB
+}
+
+// NewBoltAuthorStore - store the authors in a bolt file, which is opened by Open
+func NewBoltAuthorStore(fileName string) *BoltAuthorStore {
+	return &BoltAuthorStore{fileName: fileName}
+}
+
+// Open opens the file and creates the top level buckets. The buckets of the cache layout without
+// generations are deleted.
+func (s *BoltAuthorStore) Open() error {
+	s.writing.Lock()
+	defer s.writing.Unlock()
+	s.Lock()
+	defer s.Unlock()
+	log.Infof("Opening database '%v'.", s.fileName)
+	if s.db == nil {
+		db, err := openBolt(s.fileName)
+		if err != nil {
+			log.Errorf("ERROR opening cache file for init: %v.", err.Error())
+			return err
+		}
+		s.db = db
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		for _, name := range generationBuckets {
+			if tx.Bucket([]byte(name)) != nil {
+				log.Infof("Deleting bucket '%v' of the cache without generations.", name)
+				if err := tx.DeleteBucket([]byte(name)); err != nil {
+					return err
+				}
+			}
+		}
+		for _, name := range append([]string{generationsBucket}, topLevelBuckets...) {
+			if _, err := tx.CreateBucketIfNotExists([]byte(name)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// View runs a function in a read-only bolt transaction
+func (s *BoltAuthorStore) View(fn func(tx StoreTx) error) error {
+	s.RLock()
+	defer s.RUnlock()
+	if s.db == nil {
+		return errors.New("DB not open")
+	}
+	return s.db.View(func(tx *bolt.Tx) error {
+		return fn(boltTx{tx})
+	})
+}
+
+// Update runs a function in a read-write bolt transaction
+func (s *BoltAuthorStore) Update(fn func(tx StoreTx) error) error {
+	s.writing.Lock()
+	defer s.writing.Unlock()
+	s.RLock()
+	defer s.RUnlock()
+	if s.db == nil {
+		return errors.New("DB not open")
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return fn(boltTx{tx})
+	})
+}
+
+// Batch runs a function in a bolt batch
+func (s *BoltAuthorStore) Batch(fn func(tx StoreTx) error) error {
+	s.writing.Lock()
+	defer s.writing.Unlock()
+	s.RLock()
+	defer s.RUnlock()
+	if s.db == nil {
+		return errors.New("DB not open")
+	}
+	return s.db.Batch(func(tx *bolt.Tx) error {
+		return fn(boltTx{tx})
+	})
+}
+
+// Close closes the file
+func (s *BoltAuthorStore) Close() error {
+	s.writing.Lock()
+	defer s.writing.Unlock()
+	s.Lock()
+	defer s.Unlock()
+	if s.db == nil {
+		return errors.New("DB not open")
+	}
+	return s.db.Close()
+}
+
+// Compact copies the buckets shared by every generation and the given generations into a fresh file which replaces
+// it, since bolt never gives back the pages freed by the generations deleted by reloads. The generations left out are
+// dropped. It gives the size of the file before and after. Views keep running while the file is copied, only the swap
+// waits for them to finish.
+func (s *BoltAuthorStore) Compact(generations []string) (int64, int64, error) {
+	s.writing.Lock()
+	defer s.writing.Unlock()
+	s.RLock()
+	db := s.db
+	s.RUnlock()
+	if db == nil {
+		return 0, 0, errors.New("DB not open")
+	}
+	before, err := fileSize(s.fileName)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	compactedFileName := s.fileName + ".compacting"
+	os.Remove(compactedFileName)
+	compacted, err := openBolt(compactedFileName)
+	if err != nil {
+		return before, 0, err
+	}
+	err = db.View(func(from *bolt.Tx) error {
+		return compacted.Update(func(to *bolt.Tx) error {
+			return copyGenerations(from, to, generations)
+		})
+	})
+	if closeErr := compacted.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(compactedFileName)
+		return before, 0, err
+	}
+
+	after, err := s.swap(compactedFileName)
+	return before, after, err
+}
+
+// copyGenerations copies the state and overrides, and the info and buckets of the given generations only
+func copyGenerations(from *bolt.Tx, to *bolt.Tx, generations []string) error {
+	for _, name := range []string{stateBucket, overrideBucket} {
+		copied, err := to.CreateBucket([]byte(name))
+		if err != nil {
+			return err
+		}
+		if err := copyBucket(from.Bucket([]byte(name)), copied); err != nil {
+			return err
+		}
+	}
+	info, err := to.CreateBucket([]byte(generationInfoBucket))
+	if err != nil {
+		return err
+	}
+	gens, err := to.CreateBucket([]byte(generationsBucket))
+	if err != nil {
+		return err
+	}
+	fromGens := from.Bucket([]byte(generationsBucket))
+	if err := gens.SetSequence(fromGens.Sequence()); err != nil {
+		return err
+	}
+	for _, id := range generations {
+		if v := from.Bucket([]byte(generationInfoBucket)).Get([]byte(id)); v != nil {
+			if err := info.Put([]byte(id), v); err != nil {
+				return err
+			}
+		}
+		g := fromGens.Bucket([]byte(id))
+		if g == nil {
+			continue
+		}
+		copied, err := gens.CreateBucket([]byte(id))
+		if err != nil {
+			return err
+		}
+		if err := copyBucket(g, copied); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// swap renames the compacted file over the file, so that either of them is always in place, even when the
+// service dies halfway. The original file is linked to a backup until the compacted one is open, so it can be
+// restored. The store is left closed, to be opened again, only when neither file can be opened.
+func (s *BoltAuthorStore) swap(compactedFileName string) (int64, error) {
+	s.Lock()
+	defer s.Unlock()
+	originalFileName := s.fileName + ".original"
+	os.Remove(originalFileName)
+	if err := os.Link(s.fileName, originalFileName); err != nil {
+		os.Remove(compactedFileName)
+		return 0, err
+	}
+	if err := rename(compactedFileName, s.fileName); err != nil {
+		os.Remove(compactedFileName)
+		os.Remove(originalFileName)
+		return 0, err
+	}
+
+	if err := s.db.Close(); err != nil {
+		log.Errorf("Error closing the cache file before compaction: %v", err.Error())
+	}
+	s.db = nil
+	db, err := openBolt(s.fileName)
+	if err != nil {
+		log.Errorf("Error opening the compacted cache file, restoring the original one: %v", err.Error())
+		if restoreErr := rename(originalFileName, s.fileName); restoreErr != nil {
+			return 0, restoreErr
+		}
+		if s.db, err = openBolt(s.fileName); err != nil {
+			return 0, err
+		}
+		return 0, errors.New("The compacted cache file couldn't be opened, the original one was restored")
+	}
+	s.db = db
+	os.Remove(originalFileName)
+	return fileSize(s.fileName)
+}
+
+// copyBucket copies the keys, nested buckets and sequence of a bucket into an empty bucket. Keys are
+// copied in order, so the pages of the copy can be filled up.
+func copyBucket(from *bolt.Bucket, to *bolt.Bucket) error {
+	to.FillPercent = 1.0
+	if err := to.SetSequence(from.Sequence()); err != nil {
+		return err
+	}
+	return from.ForEach(func(k, v []byte) error {
+		if v != nil {
+			return to.Put(k, v)
+		}
+		nested, err := to.CreateBucket(k)
+		if err != nil {
+			return err
+		}
+		return copyBucket(from.Bucket(k), nested)
+	})
+}
+
+// rename renames a file, it's replaced by tests to make renames fail
+var rename = os.Rename
+
+func openBolt(fileName string) (*bolt.DB, error) {
+	return bolt.Open(fileName, 0600, &bolt.Options{Timeout: 1 * time.Second})
+}
+
+func fileSize(fileName string) (int64, error) {
+	info, err := os.Stat(fileName)
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+type boltTx struct {
+	tx *bolt.Tx
+}
+
+func (t boltTx) Bucket(name string) StoreBucket {
+	if b := t.tx.Bucket([]byte(name)); b != nil {
+		return boltBucket{b}
+	}
+	return nil
+}
+
+func (t boltTx) Generation(id string) StoreGeneration {
+	if id == "" {
+		return nil
+	}
+	if g := t.tx.Bucket([]byte(generationsBucket)).Bucket([]byte(id)); g != nil {
+		return boltGeneration{g}
+	}
+	return nil
+}
+
+func (t boltTx) CreateGeneration(id string, buckets []string) (StoreGeneration, error) {
+	g, err := t.tx.Bucket([]byte(generationsBucket)).CreateBucket([]byte(id))
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range buckets {
+		if _, err := g.CreateBucket([]byte(name)); err != nil {
+			return nil, err
+		}
+	}
+	return boltGeneration{g}, nil
+}
+
+func (t boltTx) DeleteGeneration(id string) error {
+	if err := t.tx.Bucket([]byte(generationsBucket)).DeleteBucket([]byte(id)); err != nil && err != bolt.ErrBucketNotFound {
+		return err
+	}
+	return nil
+}
+
+func (t boltTx) NextSequence() (uint64, error) {
+	return t.tx.Bucket([]byte(generationsBucket)).NextSequence()
+}
+
+type boltGeneration struct {
+	bucket *bolt.Bucket
+}
+
+func (g boltGeneration) Bucket(name string) StoreBucket {
+	if b := g.bucket.Bucket([]byte(name)); b != nil {
+		return boltBucket{b}
+	}
+	return nil
+}
+
+type boltBucket struct {
+	*bolt.Bucket
+}
+
+func (b boltBucket) Cursor() StoreCursor {
+	return b.Bucket.Cursor()
+}
+
+func (b boltBucket) Count() int {
+	return b.Stats().KeyN
+}
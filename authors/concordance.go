@@ -0,0 +1,116 @@
+package authors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Concordance - the TME identifiers of duplicate authors keyed by the TME identifier of the author they are merged into
+type Concordance map[string][]string
+
+// LoadConcordance - read a JSON concordance from a file. Without a file there are no merged authors.
+func LoadConcordance(fileName string) (Concordance, error) {
+	c := Concordance{}
+	if fileName == "" {
+		return c, nil
+	}
+	contents, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(contents, &c); err != nil {
+		return nil, fmt.Errorf("Concordance %v is not valid JSON: %v", fileName, err)
+	}
+	if _, err := c.canonicalIdentifiers(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// canonicalIdentifiers returns the canonical TME identifier of each secondary TME identifier. A secondary
+// identifier can only have one canonical identifier, and can't be canonical itself.
+func (c Concordance) canonicalIdentifiers() (map[string]string, error) {
+	canonicalOf := make(map[string]string)
+	for canonical, secondaries := range c {
+		for _, secondary := range secondaries {
+			if secondary == canonical {
+				return nil, fmt.Errorf("TME identifier %v is concorded with itself", canonical)
+			}
+			if other, ok := canonicalOf[secondary]; ok && other != canonical {
+				return nil, fmt.Errorf("TME identifier %v is concorded with both %v and %v", secondary, other, canonical)
+			}
+			canonicalOf[secondary] = canonical
+		}
+	}
+	for secondary := range canonicalOf {
+		if _, ok := c[secondary]; ok {
+			return nil, fmt.Errorf("TME identifier %v is both canonical and concorded with %v", secondary, canonicalOf[secondary])
+		}
+	}
+	return canonicalOf, nil
+}
+
+// with adds the entries of another concordance which don't conflict with this one
+func (c Concordance) with(other Concordance) Concordance {
+	combined := Concordance{}
+	for canonical, secondaries := range c {
+		combined[canonical] = append([]string{}, secondaries...)
+	}
+	canonicals := make([]string, 0, len(other))
+	for canonical := range other {
+		canonicals = append(canonicals, canonical)
+	}
+	sort.Strings(canonicals)
+	for _, canonical := range canonicals {
+		for _, secondary := range other[canonical] {
+			if contains(combined[canonical], secondary) {
+				continue
+			}
+			previous := combined[canonical]
+			combined[canonical] = append(previous, secondary)
+			if _, err := combined.canonicalIdentifiers(); err != nil {
+				log.Warnf("Ignoring curated concordance of %v: %v", secondary, err)
+				combined[canonical] = previous
+				if len(previous) == 0 {
+					delete(combined, canonical)
+				}
+			}
+		}
+	}
+	return combined
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// curatedConcordance reads the secondary TME identifiers of the curated authors
+func curatedConcordance(bAuthors []berthaAuthor) Concordance {
+	c := Concordance{}
+	for _, b := range bAuthors {
+		for _, secondary := range splitCuratedList(b.SecondaryTmeIdentifiers) {
+			c[b.TmeIdentifier] = append(c[b.TmeIdentifier], secondary)
+		}
+	}
+	return c
+}
+
+// mergeSecondary adds the identifiers, taxonomies and aliases of a duplicate author to its canonical author
+func mergeSecondary(canonical author, secondary author) author {
+	if len(secondary.Taxonomies) > 0 {
+		canonical.Taxonomies = removeDuplicates(append(canonical.Taxonomies, secondary.Taxonomies...))
+	}
+	canonical.AlternativeIdentifiers.TME = removeDuplicates(append(canonical.AlternativeIdentifiers.TME, secondary.AlternativeIdentifiers.TME...))
+	canonical.AlternativeIdentifiers.UUIDs = removeDuplicates(append(canonical.AlternativeIdentifiers.UUIDs, secondary.AlternativeIdentifiers.UUIDs...))
+	canonical.Aliases = dedupeAliases(append(canonical.Aliases, secondary.Aliases...))
+	return canonical
+}
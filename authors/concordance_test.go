@@ -0,0 +1,102 @@
+package authors
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadConcordance(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		expected Concordance
+		err      string
+	}{
+		{"Valid concordance",
+			`{"canonical": ["secondary1", "secondary2"], "other": ["secondary3"]}`,
+			Concordance{"canonical": {"secondary1", "secondary2"}, "other": {"secondary3"}},
+			""},
+		{"Concorded with itself",
+			`{"canonical": ["canonical"]}`,
+			nil,
+			"TME identifier canonical is concorded with itself"},
+		{"Concorded twice",
+			`{"canonical": ["secondary"], "other": ["secondary"]}`,
+			nil,
+			"regex=TME identifier secondary is concorded with both (canonical and other|other and canonical)"},
+		{"Chained",
+			`{"canonical": ["secondary"], "secondary": ["other"]}`,
+			nil,
+			"TME identifier secondary is both canonical and concorded with canonical"},
+		{"Invalid JSON",
+			`["secondary"]`,
+			nil,
+			"regex=is not valid JSON"},
+	}
+
+	for _, test := range tests {
+		tmpfile, err := ioutil.TempFile("", "concordance")
+		assert.NoError(t, err)
+		_, err = tmpfile.WriteString(test.config)
+		assert.NoError(t, err)
+		assert.NoError(t, tmpfile.Close())
+
+		concordance, err := LoadConcordance(tmpfile.Name())
+		os.Remove(tmpfile.Name())
+		switch {
+		case test.err == "":
+			assert.NoError(t, err, test.name)
+			assert.Equal(t, test.expected, concordance, test.name)
+		case len(test.err) > 6 && test.err[:6] == "regex=":
+			assert.Error(t, err, test.name)
+			assert.Regexp(t, test.err[6:], err.Error(), test.name)
+		default:
+			assert.EqualError(t, err, test.err, test.name)
+		}
+	}
+}
+
+func TestLoadConcordanceWithoutFile(t *testing.T) {
+	concordance, err := LoadConcordance("")
+	assert.NoError(t, err)
+	assert.Empty(t, concordance)
+}
+
+func TestConcordanceWithCuratedConcordance(t *testing.T) {
+	configured := Concordance{"canonical": {"secondary1"}}
+	curated := curatedConcordance([]berthaAuthor{
+		{TmeIdentifier: "canonical", SecondaryTmeIdentifiers: "secondary2; secondary1"},
+		{TmeIdentifier: "other", SecondaryTmeIdentifiers: "secondary1\nsecondary3"},
+		{TmeIdentifier: "nothing"},
+	})
+	assert.Equal(t, Concordance{"canonical": {"secondary2", "secondary1"}, "other": {"secondary1", "secondary3"}}, curated)
+
+	combined := configured.with(curated)
+	assert.Equal(t, Concordance{"canonical": {"secondary1", "secondary2"}, "other": {"secondary3"}}, combined)
+	assert.Equal(t, Concordance{"canonical": {"secondary1"}}, configured)
+}
+
+func TestMergeSecondary(t *testing.T) {
+	canonical := author{
+		UUID:                   "1",
+		PrefLabel:              "Martin Wolf",
+		AlternativeIdentifiers: alternativeIdentifiers{TME: []string{"tme1"}, UUIDs: []string{"1"}},
+		Aliases:                []string{"Martin Wolf"},
+	}
+	secondary := author{
+		UUID:                   "2",
+		PrefLabel:              "martin wolf",
+		AlternativeIdentifiers: alternativeIdentifiers{TME: []string{"tme2"}, UUIDs: []string{"2"}},
+		Aliases:                []string{"martin wolf", "M Wolf"},
+	}
+
+	merged := mergeSecondary(canonical, secondary)
+	assert.Equal(t, "1", merged.UUID)
+	assert.Equal(t, "Martin Wolf", merged.PrefLabel)
+	assert.Equal(t, []string{"tme1", "tme2"}, merged.AlternativeIdentifiers.TME)
+	assert.Equal(t, []string{"1", "2"}, merged.AlternativeIdentifiers.UUIDs)
+	assert.Equal(t, []string{"Martin Wolf", "M Wolf"}, merged.Aliases)
+}
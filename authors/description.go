@@ -0,0 +1,141 @@
+package authors
+
+import (
+	"bytes"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// maxShortDescriptionLength is the longest a short description can be, in characters
+const maxShortDescriptionLength = 200
+
+// blockElements start and end a paragraph of the plain text description
+var blockElements = map[string]bool{
+	"p": true, "div": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"blockquote": true, "ul": true, "ol": true, "table": true,
+}
+
+// lineElements start a line of the plain text description
+var lineElements = map[string]bool{"li": true, "tr": true}
+
+// plainText renders a description as plain text for apps: only the text of links is kept, block elements become
+// paragraphs separated by a blank line and straight quotes are replaced with typographic ones
+func plainText(description string) (string, error) {
+	nodes, err := html.ParseFragment(strings.NewReader(description), &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body})
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	for _, n := range nodes {
+		renderText(&buf, n)
+	}
+	return smartQuotes(tidyLines(buf.String())), nil
+}
+
+func renderText(buf *bytes.Buffer, n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		buf.WriteString(strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return ' '
+			}
+			return r
+		}, n.Data))
+		return
+	case html.ElementNode:
+		if removedElements[n.Data] {
+			return
+		}
+		if n.Data == "br" {
+			buf.WriteString("\n")
+			return
+		}
+	}
+
+	switch {
+	case blockElements[n.Data]:
+		buf.WriteString("\n\n")
+	case lineElements[n.Data]:
+		buf.WriteString("\n")
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		renderText(buf, c)
+	}
+	if blockElements[n.Data] {
+		buf.WriteString("\n\n")
+	}
+}
+
+// tidyLines collapses the spaces within lines and the blank lines between paragraphs
+func tidyLines(text string) string {
+	var lines []string
+	blank := true
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" {
+			if !blank {
+				lines = append(lines, "")
+			}
+			blank = true
+			continue
+		}
+		lines = append(lines, line)
+		blank = false
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// smartQuotes replaces straight quotes with opening or closing typographic quotes, depending on what precedes them
+func smartQuotes(text string) string {
+	var quoted []rune
+	previous := ' '
+	for _, r := range text {
+		opening := unicode.IsSpace(previous) || strings.ContainsRune("([{“‘—–-", previous)
+		switch {
+		case r == '"' && opening:
+			r = '“'
+		case r == '"':
+			r = '”'
+		case r == '\'' && opening:
+			r = '‘'
+		case r == '\'':
+			r = '’'
+		}
+		quoted = append(quoted, r)
+		previous = r
+	}
+	return string(quoted)
+}
+
+// shortDescription is the first sentence of a plain text description, cut at a word if it's too long
+func shortDescription(description string) string {
+	paragraph := strings.SplitN(description, "\n", 2)[0]
+	runes := []rune(paragraph)
+	for i := 0; i < len(runes)-1; i++ {
+		if strings.ContainsRune(".!?", runes[i]) && unicode.IsSpace(runes[i+1]) && !isAbbreviation(runes[:i]) {
+			runes = runes[:i+1]
+			break
+		}
+	}
+	if len(runes) <= maxShortDescriptionLength {
+		return string(runes)
+	}
+	cut := string(runes[:maxShortDescriptionLength-1])
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,;:") + "…"
+}
+
+// isAbbreviation tells whether the full stop after a text ends an initial or an honorific, as in "J. P. Rathbone" or "Dr. Who",
+// rather than a sentence
+func isAbbreviation(text []rune) bool {
+	word := string(text)
+	if i := strings.LastIndexFunc(word, unicode.IsSpace); i >= 0 {
+		word = word[i+1:]
+	}
+	return len([]rune(word)) == 1 || honorifics[strings.ToLower(word)]
+}
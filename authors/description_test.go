@@ -0,0 +1,73 @@
+package authors
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlainText(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		expected    string
+	}{
+		{"Empty", "", ""},
+		{"Plain text", "Martin Wolf writes", "Martin Wolf writes"},
+		{"Paragraphs",
+			"<p>Martin Wolf is chief economics commentator.</p>\n  <p>He was awarded the CBE in 2000.</p>",
+			"Martin Wolf is chief economics commentator.\n\nHe was awarded the CBE in 2000."},
+		{"Headings and line breaks",
+			"<h1>Biography</h1><p>Economics<br>London</p>",
+			"Biography\n\nEconomics\nLondon"},
+		{"Only the text of links",
+			`<p>Follow <a href="https://twitter.com/martinwolf_">Martin on Twitter</a>.</p>`,
+			"Follow Martin on Twitter."},
+		{"Lists",
+			"<p>Books:</p><ul><li>Why Globalization Works</li><li>Fixing Global Finance</li></ul>",
+			"Books:\n\nWhy Globalization Works\nFixing Global Finance"},
+		{"Whitespace is collapsed",
+			"<p>Martin   Wolf\n writes</p>",
+			"Martin Wolf writes"},
+		{"Entities are decoded",
+			"<p>Profits &amp; losses &ldquo;matter&rdquo;</p>",
+			"Profits & losses “matter”"},
+		{"Smart quotes",
+			`<p>He said "it's 'fixed' now" (and "twice")</p>`,
+			"He said “it’s ‘fixed’ now” (and “twice”)"},
+		{"Scripts are ignored",
+			"<p>Bio<script>alert(1)</script></p>",
+			"Bio"},
+	}
+
+	for _, test := range tests {
+		text, err := plainText(test.description)
+		assert.NoError(t, err, test.name)
+		assert.Equal(t, test.expected, text, test.name)
+	}
+}
+
+func TestShortDescription(t *testing.T) {
+	long := strings.Repeat("economics ", 30)
+	tests := []struct {
+		name        string
+		description string
+		expected    string
+	}{
+		{"Empty", "", ""},
+		{"Single sentence", "Martin Wolf writes about economics", "Martin Wolf writes about economics"},
+		{"First sentence", "Martin Wolf is chief economics commentator. He was awarded the CBE in 2000.", "Martin Wolf is chief economics commentator."},
+		{"First paragraph", "Martin Wolf is chief economics commentator\n\nHe was awarded the CBE", "Martin Wolf is chief economics commentator"},
+		{"Questions and exclamations", "Who is Martin Wolf? He writes", "Who is Martin Wolf?"},
+		{"Initials and honorifics", "J. P. Rathbone and Dr. Who write. Rarely", "J. P. Rathbone and Dr. Who write."},
+		{"Numbers", "He has 2.5 million readers. Or more", "He has 2.5 million readers."},
+		{"Cut at a word", long, strings.TrimSpace(strings.Repeat("economics ", 19)) + "…"},
+	}
+
+	for _, test := range tests {
+		short := shortDescription(test.description)
+		assert.Equal(t, test.expected, short, test.name)
+		assert.True(t, len([]rune(short)) <= maxShortDescriptionLength, test.name)
+	}
+}
@@ -0,0 +1,173 @@
+package authors
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+	"sort"
+)
+
+const (
+	authorAdded   = "added"
+	authorRemoved = "removed"
+	authorChanged = "changed"
+)
+
+// authorCursor is a cursor over the authors of a generation, nil for no generation
+func authorCursor(tx StoreTx, id string) (StoreCursor, error) {
+	if id == "" {
+		return nil, nil
+	}
+	gen, err := generationOf(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	return gen.Bucket(cacheBucket).Cursor(), nil
+}
+
+func first(c StoreCursor) ([]byte, []byte) {
+	if c == nil {
+		return nil, nil
+	}
+	return c.First()
+}
+
+// diffGenerations walks the authors of two generations in lockstep, in UUID order, and emits every author
+// which was added, removed or changed. Authors are diffed against no author at all when from is empty.
+func diffGenerations(tx StoreTx, from string, to string, emit func(authorDiff) error) error {
+	fromCursor, err := authorCursor(tx, from)
+	if err != nil {
+		return err
+	}
+	toCursor, err := authorCursor(tx, to)
+	if err != nil {
+		return err
+	}
+	fromKey, fromValue := first(fromCursor)
+	toKey, toValue := first(toCursor)
+	for fromKey != nil || toKey != nil {
+		var d authorDiff
+		switch {
+		case toKey == nil || (fromKey != nil && bytes.Compare(fromKey, toKey) < 0):
+			d, err = diffAuthor(authorRemoved, fromValue, nil)
+			fromKey, fromValue = fromCursor.Next()
+		case fromKey == nil || bytes.Compare(fromKey, toKey) > 0:
+			d, err = diffAuthor(authorAdded, nil, toValue)
+			toKey, toValue = toCursor.Next()
+		default:
+			if !bytes.Equal(fromValue, toValue) {
+				d, err = diffAuthor(authorChanged, fromValue, toValue)
+			}
+			fromKey, fromValue = fromCursor.Next()
+			toKey, toValue = toCursor.Next()
+		}
+		if err != nil {
+			return err
+		}
+		if d.Change == "" || (d.Change == authorChanged && len(d.Fields) == 0) {
+			continue
+		}
+		if err := emit(d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getDiff streams how the authors of a generation differ from the authors of an older one, as NDJSON.
+// The active generation is diffed when no generation is given.
+func (s *authorServiceImpl) getDiff(from string, to string) (io.PipeReader, bool, error) {
+	s.RLock()
+	if to == "" {
+		to = s.active
+	}
+	found := false
+	err := s.store.View(func(tx StoreTx) error {
+		info := tx.Bucket(generationInfoBucket)
+		found = info.Get([]byte(from)) != nil && info.Get([]byte(to)) != nil
+		return nil
+	})
+	if err != nil || !found {
+		s.RUnlock()
+		return io.PipeReader{}, found, err
+	}
+	return s.streamDiff(from, to), true, nil
+}
+
+// streamDiff streams the diff of two generations as NDJSON. It must be called with the read lock held,
+// which is released once the diff is streamed.
+func (s *authorServiceImpl) streamDiff(from string, to string) io.PipeReader {
+	pv, pw := io.Pipe()
+	go func() {
+		defer s.RUnlock()
+		encoder := json.NewEncoder(pw)
+		pw.CloseWithError(s.store.View(func(tx StoreTx) error {
+			return diffGenerations(tx, from, to, func(d authorDiff) error {
+				return encoder.Encode(d)
+			})
+		}))
+	}()
+	return *pv
+}
+
+// diffAuthor compares the fields of two versions of an author, either of which can be missing
+func diffAuthor(change string, from []byte, to []byte) (authorDiff, error) {
+	var fromFields, toFields map[string]interface{}
+	if from != nil {
+		if err := json.Unmarshal(from, &fromFields); err != nil {
+			return authorDiff{}, err
+		}
+	}
+	if to != nil {
+		if err := json.Unmarshal(to, &toFields); err != nil {
+			return authorDiff{}, err
+		}
+	}
+	d := authorDiff{Change: change, Fields: []fieldChange{}}
+	for _, fields := range []map[string]interface{}{fromFields, toFields} {
+		if uuid, ok := fields["uuid"].(string); ok {
+			d.UUID = uuid
+		}
+		if prefLabel, ok := fields["prefLabel"].(string); ok {
+			d.PrefLabel = prefLabel
+		}
+	}
+
+	names := make(map[string]bool)
+	for name := range fromFields {
+		names[name] = true
+	}
+	for name := range toFields {
+		names[name] = true
+	}
+	for name := range names {
+		if !reflect.DeepEqual(fromFields[name], toFields[name]) {
+			d.Fields = append(d.Fields, fieldChange{Field: name, From: fromFields[name], To: toFields[name]})
+		}
+	}
+	sort.Slice(d.Fields, func(i, j int) bool {
+		return d.Fields[i].Field < d.Fields[j].Field
+	})
+	return d, nil
+}
+
+// summarise adds an author diff to a diff summary
+func (s *diffSummary) summarise(d authorDiff) {
+	switch d.Change {
+	case authorAdded:
+		s.Added = append(s.Added, d.UUID)
+	case authorRemoved:
+		s.Removed = append(s.Removed, d.UUID)
+	default:
+		fields := make([]string, len(d.Fields))
+		for i, f := range d.Fields {
+			fields[i] = f.Field
+		}
+		s.Changed[d.UUID] = fields
+	}
+}
+
+func newDiffSummary(from string, to string) diffSummary {
+	return diffSummary{From: from, To: to, Added: []string{}, Removed: []string{}, Changed: map[string][]string{}}
+}
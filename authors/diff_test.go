@@ -0,0 +1,78 @@
+package authors
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffAuthor(t *testing.T) {
+	d, err := diffAuthor(authorChanged, []byte(`{"uuid":"1","prefLabel":"Fred","aliases":["Fred"],"type":"Person"}`),
+		[]byte(`{"uuid":"1","prefLabel":"Fred Black","aliases":["Fred"],"type":"Person","emailAddress":"fred@ft.com"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, authorDiff{UUID: "1", PrefLabel: "Fred Black", Change: authorChanged, Fields: []fieldChange{
+		{Field: "emailAddress", To: "fred@ft.com"},
+		{Field: "prefLabel", From: "Fred", To: "Fred Black"},
+	}}, d)
+
+	d, err = diffAuthor(authorRemoved, []byte(`{"uuid":"1","prefLabel":"Fred"}`), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, authorDiff{UUID: "1", PrefLabel: "Fred", Change: authorRemoved, Fields: []fieldChange{
+		{Field: "prefLabel", From: "Fred"},
+		{Field: "uuid", From: "1"},
+	}}, d)
+}
+
+func TestDryRunReload(t *testing.T) {
+	tmpfile := getTempFile(t)
+	defer os.Remove(tmpfile.Name())
+	repo := dummyRepo{terms: []term{{CanonicalName: "Bob", RawID: "bob"}, {CanonicalName: "Fred", RawID: "fred"}}}
+	service := createTestAuthorService(&repo, tmpfile.Name())
+	defer service.Shutdown()
+	waitTillInit(t, service)
+	waitTillDataLoaded(t, service)
+	_, found, err := service.getDryRunDiff()
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	repo.terms = []term{{CanonicalName: "Fred Black", RawID: "fred"}, {CanonicalName: "Terry", RawID: "terry"}}
+	repo.count = 0
+	summary, err := service.dryRunReload()
+	assert.NoError(t, err)
+	bobUUID := UUIDStrategy{}.uuidOf(buildTmeIdentifier("bob", "taxonomy_string"))
+	fredUUID := UUIDStrategy{}.uuidOf(buildTmeIdentifier("fred", "taxonomy_string"))
+	terryUUID := UUIDStrategy{}.uuidOf(buildTmeIdentifier("terry", "taxonomy_string"))
+	assert.Equal(t, []string{terryUUID}, summary.Added)
+	assert.Equal(t, []string{bobUUID}, summary.Removed)
+	assert.Contains(t, summary.Changed[fredUUID], "prefLabel")
+	assert.Len(t, summary.Changed, 1)
+
+	assert.True(t, service.isDataLoaded(), "A dry run should keep serving the authors")
+	_, found, err = service.getAuthorByUUID(bobUUID)
+	assert.NoError(t, err)
+	assert.True(t, found, "A dry run shouldn't be activated")
+	assertCount(t, service, 2)
+
+	pv, found, err := service.getDryRunDiff()
+	assert.NoError(t, err)
+	assert.True(t, found)
+	defer pv.Close()
+	var diffs []authorDiff
+	scanner := bufio.NewScanner(&pv)
+	for scanner.Scan() {
+		var d authorDiff
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &d))
+		diffs = append(diffs, d)
+	}
+	assert.NoError(t, scanner.Err())
+	assert.Len(t, diffs, 3)
+	for _, d := range diffs {
+		if d.UUID == fredUUID {
+			assert.Equal(t, authorChanged, d.Change)
+			assert.Contains(t, d.Fields, fieldChange{Field: "prefLabel", From: "Fred", To: "Fred Black"})
+		}
+	}
+}
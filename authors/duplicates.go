@@ -0,0 +1,186 @@
+package authors
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	// duplicateThreshold is the lowest name similarity for two authors to be reported as duplicates
+	duplicateThreshold = 0.9
+	// maxDuplicateBlockSize caps the number of authors sharing a name token which are compared pairwise
+	maxDuplicateBlockSize = 1000
+
+	matchedOnName        = "name"
+	matchedOnAlias       = "alias"
+	matchedOnEmail       = "email"
+	matchedOnSimilarName = "similarName"
+)
+
+type duplicateLink struct {
+	from, to  int
+	score     float64
+	matchedOn string
+}
+
+// findDuplicates groups the authors which are likely to be the same person: those sharing a name, an alias
+// or an email address, and those with very similar names. The score of a group is its weakest link.
+func findDuplicates(authors []author) []duplicateGroup {
+	links := exactLinks(authors)
+	links = append(links, similarNameLinks(authors)...)
+	sort.SliceStable(links, func(i, j int) bool {
+		return links[i].score > links[j].score
+	})
+
+	parents := make([]int, len(authors))
+	for i := range parents {
+		parents[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parents[i] != i {
+			parents[i] = find(parents[i])
+		}
+		return parents[i]
+	}
+
+	scores := make(map[int]float64)
+	for _, l := range links {
+		from, to := find(l.from), find(l.to)
+		if from == to {
+			continue
+		}
+		parents[to] = from
+		// links are sorted by score so the one joining two groups is the weakest so far
+		scores[from] = l.score
+		delete(scores, to)
+	}
+
+	groups := make(map[int]*duplicateGroup)
+	matchedOn := make(map[int]map[string]bool)
+	for _, l := range links {
+		root := find(l.from)
+		if matchedOn[root] == nil {
+			matchedOn[root] = make(map[string]bool)
+		}
+		matchedOn[root][l.matchedOn] = true
+	}
+	for i, a := range authors {
+		root := find(i)
+		if _, ok := matchedOn[root]; !ok {
+			continue
+		}
+		g, ok := groups[root]
+		if !ok {
+			g = &duplicateGroup{Score: scores[root]}
+			for m := range matchedOn[root] {
+				g.MatchedOn = append(g.MatchedOn, m)
+			}
+			sort.Strings(g.MatchedOn)
+			groups[root] = g
+		}
+		g.Authors = append(g.Authors, toDuplicateAuthor(a))
+	}
+
+	duplicates := []duplicateGroup{}
+	for _, g := range groups {
+		sort.Slice(g.Authors, func(i, j int) bool {
+			if g.Authors[i].PrefLabel != g.Authors[j].PrefLabel {
+				return g.Authors[i].PrefLabel < g.Authors[j].PrefLabel
+			}
+			return g.Authors[i].UUID < g.Authors[j].UUID
+		})
+		duplicates = append(duplicates, *g)
+	}
+	sort.Slice(duplicates, func(i, j int) bool {
+		if duplicates[i].Score != duplicates[j].Score {
+			return duplicates[i].Score > duplicates[j].Score
+		}
+		return duplicates[i].Authors[0].PrefLabel < duplicates[j].Authors[0].PrefLabel
+	})
+	return duplicates
+}
+
+// exactLinks links the authors sharing a normalised name, alias or email address. Aliases of a single
+// word, such as initials, are too ambiguous to be used.
+func exactLinks(authors []author) []duplicateLink {
+	byName := make(map[string][]int)
+	byEmail := make(map[string][]int)
+	for i, a := range authors {
+		keys := make(map[string]bool)
+		for _, name := range append([]string{a.PrefLabel}, a.Aliases...) {
+			key := normaliseName(name)
+			if len(strings.Fields(key)) > 1 && !keys[key] {
+				keys[key] = true
+				byName[key] = append(byName[key], i)
+			}
+		}
+		if email := strings.ToLower(strings.TrimSpace(a.EmailAddress)); email != "" {
+			byEmail[email] = append(byEmail[email], i)
+		}
+	}
+
+	var links []duplicateLink
+	for key, indexes := range byName {
+		for _, i := range indexes[1:] {
+			matchedOn := matchedOnAlias
+			if normaliseName(authors[indexes[0]].PrefLabel) == key && normaliseName(authors[i].PrefLabel) == key {
+				matchedOn = matchedOnName
+			}
+			links = append(links, duplicateLink{from: indexes[0], to: i, score: 1, matchedOn: matchedOn})
+		}
+	}
+	for _, indexes := range byEmail {
+		for _, i := range indexes[1:] {
+			links = append(links, duplicateLink{from: indexes[0], to: i, score: 1, matchedOn: matchedOnEmail})
+		}
+	}
+	return links
+}
+
+// similarNameLinks links the authors whose names are similar but not spelt the same. Only the authors sharing
+// a name token are compared, which keeps the number of comparisons manageable.
+func similarNameLinks(authors []author) []duplicateLink {
+	blocks := make(map[string][]int)
+	for i, a := range authors {
+		tokens := make(map[string]bool)
+		for _, t := range strings.FieldsFunc(normaliseName(a.PrefLabel), func(r rune) bool { return r == ' ' || r == ',' }) {
+			if len([]rune(t)) > 2 && !tokens[t] {
+				tokens[t] = true
+				blocks[t] = append(blocks[t], i)
+			}
+		}
+	}
+
+	var links []duplicateLink
+	compared := make(map[[2]int]bool)
+	for _, indexes := range blocks {
+		if len(indexes) > maxDuplicateBlockSize {
+			continue
+		}
+		for x, i := range indexes {
+			for _, j := range indexes[x+1:] {
+				if compared[[2]int{i, j}] {
+					continue
+				}
+				compared[[2]int{i, j}] = true
+				if normaliseName(authors[i].PrefLabel) == normaliseName(authors[j].PrefLabel) {
+					continue
+				}
+				score := nameSimilarity(authors[i].PrefLabel, authors[j].PrefLabel)
+				if score >= duplicateThreshold {
+					links = append(links, duplicateLink{from: i, to: j, score: score, matchedOn: matchedOnSimilarName})
+				}
+			}
+		}
+	}
+	return links
+}
+
+func toDuplicateAuthor(a author) duplicateAuthor {
+	d := duplicateAuthor{UUID: a.UUID, PrefLabel: a.PrefLabel, EmailAddress: a.EmailAddress}
+	if len(a.AlternativeIdentifiers.TME) > 0 {
+		d.TmeIdentifier = a.AlternativeIdentifiers.TME[0]
+	}
+	return d
+}
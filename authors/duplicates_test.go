@@ -0,0 +1,49 @@
+package authors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindDuplicates(t *testing.T) {
+	authors := []author{
+		{UUID: "1", PrefLabel: "Martin Wolf", Aliases: []string{"Martin Wolf", "MW"}},
+		{UUID: "2", PrefLabel: "martin  wolf"},
+		{UUID: "3", PrefLabel: "Martin Wolfe"},
+		{UUID: "4", PrefLabel: "John Paul Rathbone", Aliases: []string{"John Paul Rathbone", "JP Rathbone"}},
+		{UUID: "5", PrefLabel: "J. P. Rathbone", Aliases: []string{"J. P. Rathbone", "JP Rathbone"}},
+		{UUID: "6", PrefLabel: "Fred Black", EmailAddress: "fred@black.com"},
+		{UUID: "7", PrefLabel: "Frederick Black", EmailAddress: "Fred@Black.com"},
+		{UUID: "8", PrefLabel: "Terry Orange", Aliases: []string{"MW"}},
+		{UUID: "9", PrefLabel: "Lara Feigel"},
+	}
+
+	duplicates := findDuplicates(authors)
+	assert.Len(t, duplicates, 3)
+
+	assert.Equal(t, 1.0, duplicates[0].Score)
+	assert.Equal(t, []string{"email"}, duplicates[0].MatchedOn)
+	assert.Equal(t, []string{"6", "7"}, uuidsOf(duplicates[0].Authors))
+
+	assert.Equal(t, 1.0, duplicates[1].Score)
+	assert.Equal(t, []string{"alias"}, duplicates[1].MatchedOn)
+	assert.Equal(t, []string{"5", "4"}, uuidsOf(duplicates[1].Authors))
+
+	assert.InDelta(t, 0.92, duplicates[2].Score, 0.01)
+	assert.Equal(t, []string{"name", "similarName"}, duplicates[2].MatchedOn)
+	assert.Equal(t, []string{"1", "3", "2"}, uuidsOf(duplicates[2].Authors))
+}
+
+func TestFindDuplicatesWithoutDuplicates(t *testing.T) {
+	assert.Empty(t, findDuplicates([]author{{UUID: "1", PrefLabel: "Martin Wolf"}, {UUID: "2", PrefLabel: "Lara Feigel"}}))
+	assert.Empty(t, findDuplicates(nil))
+}
+
+func uuidsOf(authors []duplicateAuthor) []string {
+	var uuids []string
+	for _, a := range authors {
+		uuids = append(uuids, a.UUID)
+	}
+	return uuids
+}
@@ -0,0 +1,111 @@
+package authors
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/Financial-Times/tme-reader/tmereader"
+)
+
+// FileSourcePrefix is the prefix of a TME source which is read from local taxonomy XML files rather than TME
+const FileSourcePrefix = "file://"
+
+type fileRepository struct {
+	sync.Mutex
+	path         string
+	taxonomyName string
+	maxRecords   int
+	transformer  *AuthorTransformer
+	terms        []interface{}
+}
+
+// NewFileRepository - a TME repository reading the terms of a taxonomy from taxonomy XML files, as returned by TME.
+// The path is either a file or a directory whose XML files starting with the taxonomy name are read in order,
+// as in Authors.xml or Authors-2.xml.
+func NewFileRepository(path string, taxonomyName string, maxRecords int, transformer *AuthorTransformer) tmereader.Repository {
+	return &fileRepository{path: path, taxonomyName: taxonomyName, maxRecords: maxRecords, transformer: transformer}
+}
+
+// GetTmeTermsFromIndex returns a page of at most maxRecords terms. The files are read again when the first page is asked for.
+func (r *fileRepository) GetTmeTermsFromIndex(startRecord int) ([]interface{}, error) {
+	r.Lock()
+	defer r.Unlock()
+	if startRecord == 0 || r.terms == nil {
+		if err := r.readTerms(); err != nil {
+			return nil, err
+		}
+	}
+	if startRecord >= len(r.terms) {
+		return []interface{}{}, nil
+	}
+	end := startRecord + r.maxRecords
+	if end > len(r.terms) {
+		end = len(r.terms)
+	}
+	return r.terms[startRecord:end], nil
+}
+
+func (r *fileRepository) GetTmeTermById(rawID string) (interface{}, error) {
+	r.Lock()
+	defer r.Unlock()
+	if r.terms == nil {
+		if err := r.readTerms(); err != nil {
+			return nil, err
+		}
+	}
+	for _, t := range r.terms {
+		if t.(term).RawID == rawID {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("Term %v was not found in %v", rawID, r.path)
+}
+
+func (r *fileRepository) readTerms() error {
+	fileNames, err := r.taxonomyFiles()
+	if err != nil {
+		return err
+	}
+	terms := []interface{}{}
+	for _, fileName := range fileNames {
+		contents, err := ioutil.ReadFile(fileName)
+		if err != nil {
+			return err
+		}
+		fileTerms, err := r.transformer.UnMarshallTaxonomy(contents)
+		if err != nil {
+			return fmt.Errorf("Taxonomy file %v is not valid XML: %v", fileName, err)
+		}
+		terms = append(terms, fileTerms...)
+	}
+	r.terms = terms
+	return nil
+}
+
+func (r *fileRepository) taxonomyFiles() ([]string, error) {
+	info, err := os.Stat(r.path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{r.path}, nil
+	}
+	files, err := ioutil.ReadDir(r.path)
+	if err != nil {
+		return nil, err
+	}
+	var fileNames []string
+	for _, f := range files {
+		if !f.IsDir() && strings.HasPrefix(f.Name(), r.taxonomyName) && strings.EqualFold(filepath.Ext(f.Name()), ".xml") {
+			fileNames = append(fileNames, filepath.Join(r.path, f.Name()))
+		}
+	}
+	if len(fileNames) == 0 {
+		return nil, fmt.Errorf("No XML file for taxonomy %v in %v", r.taxonomyName, r.path)
+	}
+	return fileNames, nil
+}
@@ -0,0 +1,75 @@
+package authors
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileRepositoryPages(t *testing.T) {
+	repo := NewFileRepository("testdata/Authors.xml", "Authors", 2, &AuthorTransformer{})
+
+	terms, err := repo.GetTmeTermsFromIndex(0)
+	assert.NoError(t, err)
+	assert.Len(t, terms, 2)
+	assert.Equal(t, "Martin Wolf", terms[0].(term).CanonicalName)
+	assert.Equal(t, []alias{{Name: "Wolf, Martin"}}, terms[0].(term).Aliases.Alias)
+
+	terms, err = repo.GetTmeTermsFromIndex(2)
+	assert.NoError(t, err)
+	assert.Len(t, terms, 1)
+	assert.Equal(t, "Gillian Tett", terms[0].(term).CanonicalName)
+
+	terms, err = repo.GetTmeTermsFromIndex(4)
+	assert.NoError(t, err)
+	assert.Empty(t, terms)
+
+	found, err := repo.GetTmeTermById("Q0ItMDAwMDkwMQ==")
+	assert.NoError(t, err)
+	assert.Equal(t, "Gillian Tett", found.(term).CanonicalName)
+
+	_, err = repo.GetTmeTermById("missing")
+	assert.EqualError(t, err, "Term missing was not found in testdata/Authors.xml")
+}
+
+func TestFileRepositoryDirectory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "taxonomies")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"Authors-1.xml":    "<taxonomy><term><name>Martin Wolf</name><id>wolf</id></term></taxonomy>",
+		"Authors-2.xml":    "<taxonomy><term><name>Gillian Tett</name><id>tett</id></term></taxonomy>",
+		"Columnists-1.xml": "<taxonomy><term><name>Lucy Kellaway</name><id>kellaway</id></term></taxonomy>",
+		"Authors.txt":      "Not a taxonomy",
+	}
+	for name, contents := range files {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0644))
+	}
+
+	terms, err := NewFileRepository(dir, "Authors", 10, &AuthorTransformer{}).GetTmeTermsFromIndex(0)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{
+		term{CanonicalName: "Martin Wolf", RawID: "wolf", Raw: "<name>Martin Wolf</name><id>wolf</id>"},
+		term{CanonicalName: "Gillian Tett", RawID: "tett", Raw: "<name>Gillian Tett</name><id>tett</id>"},
+	}, terms)
+
+	_, err = NewFileRepository(dir, "Contributors", 10, &AuthorTransformer{}).GetTmeTermsFromIndex(0)
+	assert.EqualError(t, err, "No XML file for taxonomy Contributors in "+dir)
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "Columnists-1.xml"), []byte("<taxonomy><term>"), 0644))
+	_, err = NewFileRepository(dir, "Columnists", 10, &AuthorTransformer{}).GetTmeTermsFromIndex(0)
+	assert.Error(t, err)
+}
+
+func TestLoadingAuthorsFromFiles(t *testing.T) {
+	tmpfile := getTempFile(t)
+	defer os.Remove(tmpfile.Name())
+	service := createTestAuthorService(NewFileRepository("testdata", "Authors", 1, &AuthorTransformer{}), tmpfile.Name())
+	defer service.Shutdown()
+	waitTillInit(t, service)
+	waitTillDataLoaded(t, service)
+	assertCount(t, service, 3)
+}
@@ -0,0 +1,409 @@
+package authors
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	generationsBucket    = "generations"
+	generationInfoBucket = "generationInfo"
+	stateBucket          = "state"
+	activeGenerationKey  = "activeGeneration"
+
+	generationLoading = "loading"
+	generationActive  = "active"
+	generationBlocked = "blocked"
+	generationDryRun  = "dryRun"
+	generationRetired = "retired"
+)
+
+// generationBuckets are the buckets each generation of authors is made of
+var generationBuckets = []string{cacheBucket, provenanceBucket, redirectBucket, sourceBucket}
+
+// ReloadGuard - when a reloaded generation of authors is too suspicious to replace the one being served
+type ReloadGuard struct {
+	// MaxDropPercent is how much smaller than the active generation a new generation can be, in percent. The check is off when 0.
+	MaxDropPercent int
+	// MinCuratedMatches is how many curated authors have to match a TME author
+	MinCuratedMatches int
+}
+
+// check gives the reasons why a generation shouldn't replace the active generation, if any
+func (g ReloadGuard) check(active *generation, next generation) []string {
+	var reasons []string
+	if active != nil && g.MaxDropPercent > 0 && active.Count > 0 {
+		drop := float64(active.Count-next.Count) * 100 / float64(active.Count)
+		if drop > float64(g.MaxDropPercent) {
+			reasons = append(reasons, fmt.Sprintf("%d authors instead of %d, a drop of %.1f%% over the %d%% tolerated",
+				next.Count, active.Count, drop, g.MaxDropPercent))
+		}
+	}
+	if next.CuratedMatches < g.MinCuratedMatches {
+		reasons = append(reasons, fmt.Sprintf("%d curated authors match a TME author, fewer than the %d expected",
+			next.CuratedMatches, g.MinCuratedMatches))
+	}
+	return reasons
+}
+
+// curatedResults are what loading the curated authors into a generation found. They are served once the
+// generation is activated.
+type curatedResults struct {
+	curated        Concordance
+	matches        int
+	unmatched      []unmatchedCuratedAuthor
+	duplicates     []duplicateGroup
+	descriptions   []descriptionReport
+	socialProfiles []socialProfileReport
+	images         []imageReport
+}
+
+func newCuratedResults() curatedResults {
+	return curatedResults{descriptions: []descriptionReport{}, socialProfiles: []socialProfileReport{}, images: []imageReport{}}
+}
+
+// generationOf is the bucket of a generation of authors, holding its author, provenance, redirect and source buckets
+func generationOf(tx StoreTx, id string) (StoreGeneration, error) {
+	if g := tx.Generation(id); g != nil {
+		return g, nil
+	}
+	return nil, fmt.Errorf("Generation [%v] not found!", id)
+}
+
+func readGeneration(tx StoreTx, id string) (generation, error) {
+	var g generation
+	cachedGeneration := tx.Bucket(generationInfoBucket).Get([]byte(id))
+	if cachedGeneration == nil {
+		return g, fmt.Errorf("Generation [%v] not found!", id)
+	}
+	err := json.Unmarshal(cachedGeneration, &g)
+	return g, err
+}
+
+func putGeneration(tx StoreTx, g generation) error {
+	marshalledGeneration, err := json.Marshal(g)
+	if err != nil {
+		return err
+	}
+	return tx.Bucket(generationInfoBucket).Put([]byte(g.ID), marshalledGeneration)
+}
+
+func deleteGeneration(tx StoreTx, id string) error {
+	log.Infof("Deleting generation %v.", id)
+	if err := tx.DeleteGeneration(id); err != nil {
+		return err
+	}
+	return tx.Bucket(generationInfoBucket).Delete([]byte(id))
+}
+
+// activate makes a generation the one being served and retires the generation served so far. Only the
+// most recent kept generations stay retired, older ones are deleted.
+func activate(tx StoreTx, g generation, previous string, kept int) (generation, error) {
+	if previous != "" && previous != g.ID {
+		p, err := readGeneration(tx, previous)
+		if err != nil {
+			return g, err
+		}
+		p.Status = generationRetired
+		if err := putGeneration(tx, p); err != nil {
+			return g, err
+		}
+		if err := deleteRetiredGenerations(tx, kept); err != nil {
+			return g, err
+		}
+	}
+	activatedAt := time.Now()
+	g.Status = generationActive
+	g.ActivatedAt = &activatedAt
+	if err := putGeneration(tx, g); err != nil {
+		return g, err
+	}
+	return g, tx.Bucket(stateBucket).Put([]byte(activeGenerationKey), []byte(g.ID))
+}
+
+// deleteRetiredGenerations deletes the retired generations but the most recent kept ones
+func deleteRetiredGenerations(tx StoreTx, kept int) error {
+	var retired []string
+	c := tx.Bucket(generationInfoBucket).Cursor()
+	for k, v := c.Last(); k != nil; k, v = c.Prev() {
+		var g generation
+		if err := json.Unmarshal(v, &g); err != nil {
+			return err
+		}
+		if g.Status == generationRetired {
+			retired = append(retired, g.ID)
+		}
+	}
+	for i := kept; i < len(retired); i++ {
+		if err := deleteGeneration(tx, retired[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compactedGenerations are the generations a compaction keeps: the active and blocked ones, and the most recent
+// kept retired ones
+func compactedGenerations(tx StoreTx, kept int) ([]string, error) {
+	var ids []string
+	retired := 0
+	c := tx.Bucket(generationInfoBucket).Cursor()
+	for k, v := c.Last(); k != nil; k, v = c.Prev() {
+		var g generation
+		if err := json.Unmarshal(v, &g); err != nil {
+			return nil, err
+		}
+		switch g.Status {
+		case generationActive, generationBlocked:
+			ids = append(ids, g.ID)
+		case generationRetired:
+			if retired < kept {
+				ids = append(ids, g.ID)
+			}
+			retired++
+		}
+	}
+	return ids, nil
+}
+
+// readActiveGeneration reads which generation is active in the cache. Its authors are only served once it's
+// activated, which a cache too old to serve never is.
+func (s *authorServiceImpl) readActiveGeneration() error {
+	return s.store.View(func(tx StoreTx) error {
+		s.active = string(tx.Bucket(stateBucket).Get([]byte(activeGenerationKey)))
+		return nil
+	})
+}
+
+// newGeneration creates the generation a reload loads the authors into. Generations which were never
+// activated, such as dry runs and blocked reloads, are deleted. A dry run keeps the blocked reload, which
+// can still be activated.
+func (s *authorServiceImpl) newGeneration(startedAt time.Time, dryRun bool) error {
+	s.Lock()
+	defer s.Unlock()
+	var id string
+	err := s.store.Update(func(tx StoreTx) error {
+		info := tx.Bucket(generationInfoBucket)
+		var stale []string
+		if err := info.ForEach(func(k, v []byte) error {
+			var g generation
+			if err := json.Unmarshal(v, &g); err != nil {
+				return err
+			}
+			kept := g.Status == generationActive || g.Status == generationRetired || (dryRun && g.Status == generationBlocked)
+			if !kept {
+				stale = append(stale, g.ID)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		for _, staleID := range stale {
+			if err := deleteGeneration(tx, staleID); err != nil {
+				return err
+			}
+		}
+
+		seq, err := tx.NextSequence()
+		if err != nil {
+			return err
+		}
+		id = fmt.Sprintf("%08d", seq)
+		if _, err := tx.CreateGeneration(id, generationBuckets); err != nil {
+			return err
+		}
+		return putGeneration(tx, generation{ID: id, Status: generationLoading, StartedAt: startedAt, DryRun: dryRun})
+	})
+	if err != nil {
+		return err
+	}
+	log.Infof("Loading authors into generation %v.", id)
+	s.building = id
+	s.next = newCuratedResults()
+	if !dryRun {
+		s.blocked = nil
+	}
+	s.dryRun = nil
+	return nil
+}
+
+// abandonGeneration gives up the generation of a failed reload. It is deleted by the next reload.
+func (s *authorServiceImpl) abandonGeneration() {
+	s.Lock()
+	defer s.Unlock()
+	log.Warnf("Abandoning generation %v.", s.building)
+	s.building = ""
+}
+
+// finishGeneration counts the authors of the generation loaded by the last reload and activates it,
+// unless it's a dry run or the reload guard blocks it
+func (s *authorServiceImpl) finishGeneration() (generation, error) {
+	s.Lock()
+	defer s.Unlock()
+	var g generation
+	err := s.store.Update(func(tx StoreTx) error {
+		var err error
+		if g, err = readGeneration(tx, s.building); err != nil {
+			return err
+		}
+		gen, err := generationOf(tx, s.building)
+		if err != nil {
+			return err
+		}
+		finishedAt := time.Now()
+		g.FinishedAt = &finishedAt
+		g.Count = gen.Bucket(cacheBucket).Count()
+		g.CuratedMatches = s.next.matches
+		if g.DryRun {
+			g.Status = generationDryRun
+			return putGeneration(tx, g)
+		}
+		if g.ID == s.active {
+			return putGeneration(tx, g)
+		}
+
+		active, err := s.readActive(tx)
+		if err != nil {
+			return err
+		}
+		if g.BlockedReasons = s.guard.check(active, g); len(g.BlockedReasons) > 0 {
+			g.Status = generationBlocked
+			return putGeneration(tx, g)
+		}
+		g, err = activate(tx, g, s.active, s.keptGenerations)
+		return err
+	})
+	if err != nil {
+		return g, err
+	}
+	results := s.next
+	g.results = &results
+	switch {
+	case g.Status == generationDryRun:
+		log.Infof("Generation %v of %d authors was loaded as a dry run.", g.ID, g.Count)
+		s.dryRun = &g
+	case g.Status == generationBlocked:
+		log.Errorf("Generation %v was not activated: %v", g.ID, g.BlockedReasons)
+		s.blocked = &g
+	case g.ID == s.active:
+		log.Infof("Active generation %v now has %d authors.", g.ID, g.Count)
+		s.served = results
+	default:
+		s.activated(g)
+	}
+	return g, nil
+}
+
+// readActive reads the active generation, if any
+func (s *authorServiceImpl) readActive(tx StoreTx) (*generation, error) {
+	if s.active == "" {
+		return nil, nil
+	}
+	a, err := readGeneration(tx, s.active)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// activateBlockedGeneration activates the generation blocked by the reload guard, if any
+func (s *authorServiceImpl) activateBlockedGeneration() (generation, bool, error) {
+	s.Lock()
+	defer s.Unlock()
+	if s.blocked == nil {
+		return generation{}, false, nil
+	}
+	g := *s.blocked
+	err := s.store.Update(func(tx StoreTx) error {
+		var err error
+		g, err = activate(tx, g, s.active, s.keptGenerations)
+		return err
+	})
+	if err != nil {
+		return generation{}, true, err
+	}
+	log.Warnf("Generation %v was activated even though: %v", g.ID, g.BlockedReasons)
+	s.blocked = nil
+	s.activated(g)
+	return g, true, nil
+}
+
+// serveCachedGeneration serves the active generation of an existing cache until the first reload is over,
+// provided it finished loading less than maxAge ago
+func (s *authorServiceImpl) serveCachedGeneration(maxAge time.Duration) (bool, error) {
+	s.reloading.Lock()
+	defer s.reloading.Unlock()
+	if err := s.openDB(); err != nil {
+		return false, err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	if s.active == "" {
+		return false, nil
+	}
+	var g generation
+	err := s.store.View(func(tx StoreTx) error {
+		var err error
+		if g, err = readGeneration(tx, s.active); err != nil {
+			return err
+		}
+		_, err = generationOf(tx, s.active)
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+	if g.FinishedAt == nil || g.Count == 0 {
+		log.Infof("Generation %v of the cache is incomplete, it won't be served before the reload.", g.ID)
+		return false, nil
+	}
+	if age := time.Since(*g.FinishedAt); age > maxAge {
+		log.Infof("Generation %v of the cache was loaded %v ago, it won't be served before the reload.", g.ID, age)
+		return false, nil
+	}
+	s.activated(g)
+	return true, nil
+}
+
+func (s *authorServiceImpl) activated(g generation) {
+	log.Infof("Generation %v of %d authors is now active.", g.ID, g.Count)
+	s.active = g.ID
+	if g.results != nil {
+		s.served = *g.results
+	}
+	if s.initialised {
+		s.dataLoaded = true
+	}
+}
+
+func (s *authorServiceImpl) getBlockedGeneration() (generation, bool) {
+	s.RLock()
+	defer s.RUnlock()
+	if s.blocked == nil {
+		return generation{}, false
+	}
+	return *s.blocked, true
+}
+
+// getGenerations lists the generations of authors in the cache, the most recent first
+func (s *authorServiceImpl) getGenerations() ([]generation, error) {
+	s.RLock()
+	defer s.RUnlock()
+	generations := []generation{}
+	err := s.store.View(func(tx StoreTx) error {
+		c := tx.Bucket(generationInfoBucket).Cursor()
+		for k, v := c.Last(); k != nil; k, v = c.Prev() {
+			var g generation
+			if err := json.Unmarshal(v, &g); err != nil {
+				return err
+			}
+			generations = append(generations, g)
+		}
+		return nil
+	})
+	return generations, err
+}
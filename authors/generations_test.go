@@ -0,0 +1,235 @@
+package authors
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Financial-Times/tme-reader/tmereader"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReloadGuardCheck(t *testing.T) {
+	guard := ReloadGuard{MaxDropPercent: 20, MinCuratedMatches: 2}
+	active := &generation{Count: 100}
+	assert.Empty(t, guard.check(active, generation{Count: 80, CuratedMatches: 2}))
+	assert.Empty(t, guard.check(nil, generation{Count: 1, CuratedMatches: 2}))
+	assert.Equal(t, []string{"79 authors instead of 100, a drop of 21.0% over the 20% tolerated"}, guard.check(active, generation{Count: 79, CuratedMatches: 2}))
+	assert.Equal(t, []string{"1 curated authors match a TME author, fewer than the 2 expected"}, guard.check(active, generation{Count: 120, CuratedMatches: 1}))
+	assert.Empty(t, ReloadGuard{}.check(active, generation{}), "The guard should be off by default")
+}
+
+func TestCompactedGenerations(t *testing.T) {
+	store := NewMemoryAuthorStore()
+	assert.NoError(t, store.Open())
+	statuses := []string{generationRetired, generationRetired, generationRetired, generationActive, generationLoading, generationBlocked}
+	assert.NoError(t, store.Update(func(tx StoreTx) error {
+		for i, status := range statuses {
+			if err := putGeneration(tx, generation{ID: fmt.Sprintf("%08d", i+1), Status: status}); err != nil {
+				return err
+			}
+		}
+		ids, err := compactedGenerations(tx, 2)
+		assert.Equal(t, []string{"00000006", "00000004", "00000003", "00000002"}, ids)
+		return err
+	}))
+}
+
+func createGuardedTestService(repo *dummyRepo, cacheFileName string, guard ReloadGuard) AuthorService {
+	return createAuthorService([]Taxonomy{{Name: "taxonomy_string", Repository: repo}}, NewBoltAuthorStore(cacheFileName), &mockClient{resp: []berthaAuthor{}}, ServiceOptions{Guard: guard})
+}
+
+func TestBlockedReloadKeepsServingActiveGeneration(t *testing.T) {
+	tmpfile := getTempFile(t)
+	defer os.Remove(tmpfile.Name())
+	repo := dummyRepo{terms: []term{{CanonicalName: "Bob", RawID: "bob"}, {CanonicalName: "Fred", RawID: "fred"}, {CanonicalName: "Terry", RawID: "terry"}}}
+	service := createGuardedTestService(&repo, tmpfile.Name(), ReloadGuard{MaxDropPercent: 50})
+	defer service.Shutdown()
+	waitTillInit(t, service)
+	waitTillDataLoaded(t, service)
+	assertCount(t, service, 3)
+	_, blocked := service.getBlockedGeneration()
+	assert.False(t, blocked)
+
+	repo.terms = repo.terms[:1]
+	repo.count = 0
+	assert.NoError(t, service.reloadDB())
+	if c := service.getReloadReport().Compaction; assert.NotNil(t, c, "The cache should be compacted after the reload") {
+		assert.Empty(t, c.Error)
+		assert.True(t, c.SizeAfter > 0)
+	}
+	assert.True(t, service.isDataLoaded())
+	assertCount(t, service, 3)
+	g, blocked := service.getBlockedGeneration()
+	assert.True(t, blocked)
+	assert.Equal(t, "blocked", g.Status)
+	assert.Equal(t, 1, g.Count)
+	assert.Equal(t, []string{"1 authors instead of 3, a drop of 66.7% over the 50% tolerated"}, g.BlockedReasons)
+
+	repo.count = 0
+	_, err := service.dryRunReload()
+	assert.NoError(t, err)
+	_, blocked = service.getBlockedGeneration()
+	assert.True(t, blocked, "A dry run should keep the blocked reload")
+
+	g, found, err := service.activateBlockedGeneration()
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "active", g.Status)
+	assertCount(t, service, 1)
+	_, blocked = service.getBlockedGeneration()
+	assert.False(t, blocked)
+	_, found, err = service.activateBlockedGeneration()
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestOverridesReachTheBlockedGeneration(t *testing.T) {
+	tmpfile := getTempFile(t)
+	defer os.Remove(tmpfile.Name())
+	repo := dummyRepo{terms: []term{
+		{CanonicalName: "Bob", RawID: "bob", Raw: "<name>Bob</name><id>bob</id>"},
+		{CanonicalName: "Fred", RawID: "fred", Raw: "<name>Fred</name><id>fred</id>"},
+		{CanonicalName: "Terry", RawID: "terry", Raw: "<name>Terry</name><id>terry</id>"},
+	}}
+	service := createGuardedTestService(&repo, tmpfile.Name(), ReloadGuard{MaxDropPercent: 50})
+	defer service.Shutdown()
+	waitTillInit(t, service)
+	waitTillDataLoaded(t, service)
+
+	bobUUID := UUIDStrategy{}.uuidOf(buildTmeIdentifier("bob", "taxonomy_string"))
+	name := "Robert"
+	_, found, err := service.saveOverride(bobUUID, overrideRequest{Fields: map[string]*string{"name": &name}, User: "jane.doe", Reason: "Full name"}, true)
+	assert.NoError(t, err)
+	assert.True(t, found)
+
+	repo.terms = repo.terms[:1]
+	repo.count = 0
+	assert.NoError(t, service.reloadDB())
+	_, blocked := service.getBlockedGeneration()
+	assert.True(t, blocked)
+
+	found, err = service.deleteOverride(bobUUID)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	prefLabel := "Bobby"
+	_, found, err = service.saveOverride(bobUUID, overrideRequest{Fields: map[string]*string{"prefLabel": &prefLabel}, User: "jane.doe", Reason: "Nickname"}, true)
+	assert.NoError(t, err)
+	assert.True(t, found)
+
+	_, found, err = service.activateBlockedGeneration()
+	assert.NoError(t, err)
+	assert.True(t, found)
+	bob, found, err := service.getAuthorByUUID(bobUUID)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "Bob", bob.Name, "The override deleted while the reload was blocked should stay deleted")
+	assert.Equal(t, "Bobby", bob.PrefLabel, "The override saved while the reload was blocked should be applied")
+}
+
+func TestFirstReloadWithoutCuratedMatchesIsBlocked(t *testing.T) {
+	tmpfile := getTempFile(t)
+	defer os.Remove(tmpfile.Name())
+	repo := dummyRepo{terms: []term{{CanonicalName: "Bob", RawID: "bob"}}}
+	service := createGuardedTestService(&repo, tmpfile.Name(), ReloadGuard{MinCuratedMatches: 1})
+	defer service.Shutdown()
+	waitTillInit(t, service)
+	waitTillReloadFinished(t, service)
+	assert.False(t, service.isDataLoaded())
+	assertCount(t, service, 0)
+	g, blocked := service.getBlockedGeneration()
+	assert.True(t, blocked)
+	assert.Equal(t, []string{"0 curated authors match a TME author, fewer than the 1 expected"}, g.BlockedReasons)
+
+	_, found, err := service.activateBlockedGeneration()
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.True(t, service.isDataLoaded())
+	assertCount(t, service, 1)
+}
+
+func TestDiffGenerations(t *testing.T) {
+	repo := dummyRepo{terms: []term{{CanonicalName: "Bob", RawID: "bob"}, {CanonicalName: "Fred", RawID: "fred"}}}
+	service := createAuthorService([]Taxonomy{{Name: "taxonomy_string", Repository: &repo}}, NewMemoryAuthorStore(), &mockClient{resp: []berthaAuthor{}}, ServiceOptions{KeptGenerations: 1})
+	defer service.Shutdown()
+	waitTillInit(t, service)
+	waitTillDataLoaded(t, service)
+
+	repo.terms = []term{{CanonicalName: "Fred", RawID: "fred"}, {CanonicalName: "Terry", RawID: "terry"}}
+	repo.count = 0
+	assert.NoError(t, service.reloadDB())
+	repo.count = 0
+	assert.NoError(t, service.reloadDB())
+
+	generations, err := service.getGenerations()
+	assert.NoError(t, err)
+	assert.Len(t, generations, 2, "Only one retired generation should be kept")
+	assert.Equal(t, "00000003", generations[0].ID)
+	assert.Equal(t, "active", generations[0].Status)
+	assert.Equal(t, "00000002", generations[1].ID)
+	assert.Equal(t, "retired", generations[1].Status)
+
+	_, found, err := service.getDiff("00000001", "")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	pv, found, err := service.getDiff("00000002", "")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	diff, err := ioutil.ReadAll(&pv)
+	assert.NoError(t, err)
+	assert.Empty(t, string(diff), "Nothing changed between the last two reloads")
+}
+
+func createStartupTestService(repo tmereader.Repository, cacheFileName string, guard ReloadGuard, startup StartupOptions) AuthorService {
+	return createAuthorService([]Taxonomy{{Name: "taxonomy_string", Repository: repo}}, NewBoltAuthorStore(cacheFileName), &mockClient{resp: []berthaAuthor{}}, ServiceOptions{Guard: guard, Startup: startup})
+}
+
+func TestServeFreshCacheOnStartup(t *testing.T) {
+	tmpfile := getTempFile(t)
+	defer os.Remove(tmpfile.Name())
+	repo := dummyRepo{terms: []term{{CanonicalName: "Bob", RawID: "bob"}, {CanonicalName: "Fred", RawID: "fred"}}}
+	service := createTestAuthorService(&repo, tmpfile.Name())
+	waitTillInit(t, service)
+	waitTillDataLoaded(t, service)
+	assert.NoError(t, service.Shutdown())
+
+	slowRepo := blockingRepo{}
+	slowRepo.Add(1)
+	restarted := createStartupTestService(&slowRepo, tmpfile.Name(), ReloadGuard{}, StartupOptions{MaxCacheAge: time.Hour})
+	defer func() {
+		slowRepo.Done()
+		restarted.Shutdown()
+	}()
+	waitTillInit(t, restarted)
+	waitTillDataLoaded(t, restarted)
+	assertCount(t, restarted, 2)
+	h := NewAuthorHandler(restarted)
+	assert.True(t, h.GTG().GoodToGo)
+}
+
+func TestStaleCacheIsNotServedOnStartup(t *testing.T) {
+	tmpfile := getTempFile(t)
+	defer os.Remove(tmpfile.Name())
+	repo := dummyRepo{terms: []term{{CanonicalName: "Bob", RawID: "bob"}}}
+	service := createTestAuthorService(&repo, tmpfile.Name())
+	waitTillInit(t, service)
+	waitTillDataLoaded(t, service)
+	assert.NoError(t, service.Shutdown())
+
+	slowRepo := blockingRepo{}
+	slowRepo.Add(1)
+	restarted := createStartupTestService(&slowRepo, tmpfile.Name(), ReloadGuard{}, StartupOptions{MaxCacheAge: time.Nanosecond})
+	defer restarted.Shutdown()
+	waitTillInit(t, restarted)
+	for i := 1; i <= 1000 && restarted.getReloadReport().StartedAt.IsZero(); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.False(t, restarted.isDataLoaded(), "The cache is too old to be served before the reload")
+
+	slowRepo.Done()
+	waitTillReloadFinished(t, restarted)
+	assert.True(t, restarted.isDataLoaded())
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"strconv"
+	"strings"
 
 	fthealth "github.com/Financial-Times/go-fthealth/v1_1"
 	"github.com/Financial-Times/service-status-go/gtg"
@@ -105,6 +106,66 @@ func (h *AuthorHandler) HealthCheck() fthealth.Check {
 	}
 }
 
+// UnmatchedCuratedAuthorsCheck - Report curated authors that don't match any TME author
+func (h *AuthorHandler) UnmatchedCuratedAuthorsCheck() fthealth.Check {
+	return fthealth.Check{
+		BusinessImpact:   "Some curated author information is not attached to the V1 author it was written for",
+		Name:             "Check all curated authors match a TME author.",
+		PanicGuide:       "TBD",
+		Severity:         3,
+		TechnicalSummary: "Curated authors whose TME identifier doesn't match any TME author. See /transformers/authors/__unmatched for the list and the closest TME matches.",
+		Checker: func() (string, error) {
+			count := len(h.service.getUnmatchedCuratedAuthors())
+			if count == 0 {
+				return "All curated authors match a TME author", nil
+			}
+			msg := fmt.Sprintf("%d curated authors do not match any TME author", count)
+			return msg, errors.New(msg)
+		},
+	}
+}
+
+// BlockedReloadCheck - Report a reload whose authors were not activated by the reload guard
+func (h *AuthorHandler) BlockedReloadCheck() fthealth.Check {
+	return fthealth.Check{
+		BusinessImpact:   "Authors are served from an older reload",
+		Name:             "Check the last reload was activated.",
+		PanicGuide:       "TBD",
+		Severity:         2,
+		TechnicalSummary: "The last reload lost too many authors or curated matches compared with the active one, so it was not activated. Check TME and Bertha, then reload or activate it with POST /transformers/authors/__reload/activate.",
+		Checker: func() (string, error) {
+			g, blocked := h.service.getBlockedGeneration()
+			if !blocked {
+				return "The last reload was activated", nil
+			}
+			msg := fmt.Sprintf("Generation %v was not activated: %v", g.ID, strings.Join(g.BlockedReasons, "; "))
+			return msg, errors.New(msg)
+		},
+	}
+}
+
+// CacheCompactionCheck - Report a compaction of the cache file which failed after the last reload
+func (h *AuthorHandler) CacheCompactionCheck() fthealth.Check {
+	return fthealth.Check{
+		BusinessImpact:   "The cache file grows with each reload until its volume is full",
+		Name:             "Check the cache file was compacted after the last reload.",
+		PanicGuide:       "TBD",
+		Severity:         3,
+		TechnicalSummary: "The cache file could not be compacted after the last reload. See the compaction in /transformers/authors/__report and the logs.",
+		Checker: func() (string, error) {
+			c := h.service.getReloadReport().Compaction
+			if c == nil {
+				return "The cache file was not compacted after the last reload", nil
+			}
+			if c.Error != "" {
+				msg := fmt.Sprintf("Compacting the cache file of %d bytes failed: %v", c.SizeBefore, c.Error)
+				return msg, errors.New(msg)
+			}
+			return fmt.Sprintf("The cache file was compacted from %d to %d bytes", c.SizeBefore, c.SizeAfter), nil
+		},
+	}
+}
+
 // GTG - Return FT standard good-to-go check
 func (h *AuthorHandler) GTG() gtg.Status {
 	statusCheck := func() gtg.Status {
@@ -145,17 +206,219 @@ func (h *AuthorHandler) GetAuthorByUUID(writer http.ResponseWriter, req *http.Re
 	obj, found, err := h.service.getAuthorByUUID(uuid)
 	if err != nil {
 		writeJSONMessageWithStatus(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		canonicalUUID, merged, err := h.service.getCanonicalUUID(uuid)
+		if err != nil {
+			writeJSONMessageWithStatus(writer, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if merged {
+			writer.Header().Set("Location", strings.Replace(req.URL.Path, uuid, canonicalUUID, 1))
+			writeJSONMessageWithStatus(writer, "Author merged into "+canonicalUUID, http.StatusMovedPermanently)
+			return
+		}
+	}
+	writeJSONResponse(obj, found, writer)
+}
+
+// GetAuthorProvenance - Return where each field of a single author came from
+func (h *AuthorHandler) GetAuthorProvenance(writer http.ResponseWriter, req *http.Request) {
+	writer.Header().Add("Content-Type", "application/json")
+	if !h.service.isInitialised() {
+		writeStatusServiceUnavailable(writer)
+		return
+	}
+
+	vars := mux.Vars(req)
+	uuid := vars["uuid"]
+
+	obj, found, err := h.service.getAuthorProvenance(uuid)
+	if err != nil {
+		writeJSONMessageWithStatus(writer, err.Error(), http.StatusInternalServerError)
+		return
 	}
 	writeJSONResponse(obj, found, writer)
 }
 
-// Reload - Reload the cache with fresh information
+// GetAuthorSource - Return the raw TME terms and Bertha row a single author was transformed from
+func (h *AuthorHandler) GetAuthorSource(writer http.ResponseWriter, req *http.Request) {
+	writer.Header().Add("Content-Type", "application/json")
+	if !h.service.isInitialised() {
+		writeStatusServiceUnavailable(writer)
+		return
+	}
+
+	vars := mux.Vars(req)
+	uuid := vars["uuid"]
+
+	obj, found, err := h.service.getAuthorSource(uuid)
+	if err != nil {
+		writeJSONMessageWithStatus(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSONResponse(obj, found, writer)
+}
+
+// PutOverride - Replace the editorial overrides of a single author
+func (h *AuthorHandler) PutOverride(writer http.ResponseWriter, req *http.Request) {
+	h.saveOverride(writer, req, true)
+}
+
+// PatchOverride - Add to or change the editorial overrides of a single author
+func (h *AuthorHandler) PatchOverride(writer http.ResponseWriter, req *http.Request) {
+	h.saveOverride(writer, req, false)
+}
+
+func (h *AuthorHandler) saveOverride(writer http.ResponseWriter, req *http.Request, replace bool) {
+	writer.Header().Add("Content-Type", "application/json")
+	if !h.service.isInitialised() {
+		writeStatusServiceUnavailable(writer)
+		return
+	}
+
+	var overrideReq overrideRequest
+	if err := json.NewDecoder(req.Body).Decode(&overrideReq); err != nil {
+		writeJSONMessageWithStatus(writer, "Invalid override: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := validateOverrideRequest(overrideReq); err != nil {
+		writeJSONMessageWithStatus(writer, "Invalid override: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	overrideReq, err := h.service.normaliseOverride(overrideReq)
+	if err != nil {
+		writeJSONMessageWithStatus(writer, "Invalid override: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	vars := mux.Vars(req)
+	uuid := vars["uuid"]
+
+	obj, found, err := h.service.saveOverride(uuid, overrideReq, replace)
+	if err != nil {
+		writeJSONMessageWithStatus(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSONResponse(obj, found, writer)
+}
+
+// DeleteOverride - Remove the editorial overrides of a single author, restoring it from its sources
+func (h *AuthorHandler) DeleteOverride(writer http.ResponseWriter, req *http.Request) {
+	writer.Header().Add("Content-Type", "application/json")
+	if !h.service.isInitialised() {
+		writeStatusServiceUnavailable(writer)
+		return
+	}
+
+	vars := mux.Vars(req)
+	uuid := vars["uuid"]
+
+	found, err := h.service.deleteOverride(uuid)
+	if err != nil {
+		writeJSONMessageWithStatus(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		writeJSONMessageWithStatus(writer, "Override not found", http.StatusNotFound)
+		return
+	}
+	writeJSONMessageWithStatus(writer, "Override removed, the author was restored from its sources", http.StatusOK)
+}
+
+// GetOverrides - Return all the active editorial overrides
+func (h *AuthorHandler) GetOverrides(writer http.ResponseWriter, req *http.Request) {
+	writer.Header().Add("Content-Type", "application/json")
+	if !h.service.isInitialised() {
+		writeStatusServiceUnavailable(writer)
+		return
+	}
+
+	overrides, err := h.service.getOverrides()
+	if err != nil {
+		writeJSONMessageWithStatus(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSONResponse(overrides, true, writer)
+}
+
+// GetUnmatchedCuratedAuthors - Return the curated authors that don't match any TME author, with their closest TME matches
+func (h *AuthorHandler) GetUnmatchedCuratedAuthors(writer http.ResponseWriter, req *http.Request) {
+	writer.Header().Add("Content-Type", "application/json")
+	if !h.service.isInitialised() {
+		writeStatusServiceUnavailable(writer)
+		return
+	}
+
+	unmatched := h.service.getUnmatchedCuratedAuthors()
+	if unmatched == nil {
+		unmatched = []unmatchedCuratedAuthor{}
+	}
+	writeJSONResponse(unmatched, true, writer)
+}
+
+// GetDuplicates - Return the groups of authors which are likely to be the same person under different TME terms
+func (h *AuthorHandler) GetDuplicates(writer http.ResponseWriter, req *http.Request) {
+	writer.Header().Add("Content-Type", "application/json")
+	if !h.service.isInitialised() || !h.service.isDataLoaded() {
+		writeStatusServiceUnavailable(writer)
+		return
+	}
+
+	duplicates := h.service.getDuplicates()
+	if duplicates == nil {
+		duplicates = []duplicateGroup{}
+	}
+	writeJSONResponse(duplicates, true, writer)
+}
+
+// GetUUIDMismatches - Return the authors whose UUID doesn't match the UUID strategy
+func (h *AuthorHandler) GetUUIDMismatches(writer http.ResponseWriter, req *http.Request) {
+	writer.Header().Add("Content-Type", "application/json")
+	if !h.service.isInitialised() || !h.service.isDataLoaded() {
+		writeStatusServiceUnavailable(writer)
+		return
+	}
+
+	mismatches, err := h.service.checkUUIDs()
+	if err != nil {
+		writeJSONMessageWithStatus(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSONResponse(mismatches, true, writer)
+}
+
+// GetReloadReport - Return what was changed or rejected during the last reload
+func (h *AuthorHandler) GetReloadReport(writer http.ResponseWriter, req *http.Request) {
+	writer.Header().Add("Content-Type", "application/json")
+	if !h.service.isInitialised() {
+		writeStatusServiceUnavailable(writer)
+		return
+	}
+
+	writeJSONResponse(h.service.getReloadReport(), true, writer)
+}
+
+// Reload - Reload the cache with fresh information. A dry run loads the authors without serving them
+// and answers with how they differ from the authors being served.
 func (h *AuthorHandler) Reload(writer http.ResponseWriter, req *http.Request) {
 	if !h.service.isInitialised() || !h.service.isDataLoaded() {
 		writeStatusServiceUnavailable(writer)
 		return
 	}
 
+	if dryRun, _ := strconv.ParseBool(req.URL.Query().Get("dryRun")); dryRun {
+		writer.Header().Add("Content-Type", "application/json")
+		summary, err := h.service.dryRunReload()
+		if err != nil {
+			writeJSONMessageWithStatus(writer, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSONResponse(summary, true, writer)
+		return
+	}
+
 	go func() {
 		if err := h.service.reloadDB(); err != nil {
 			log.Errorf("ERROR opening db: %v", err.Error())
@@ -164,6 +427,139 @@ func (h *AuthorHandler) Reload(writer http.ResponseWriter, req *http.Request) {
 	writeJSONMessageWithStatus(writer, "Reloading authors", http.StatusAccepted)
 }
 
+// GetDryRunDiff - Return how each author of the last dry run differs from the authors being served, as NDJSON
+func (h *AuthorHandler) GetDryRunDiff(writer http.ResponseWriter, req *http.Request) {
+	if !h.service.isInitialised() {
+		writer.Header().Add("Content-Type", "application/json")
+		writeStatusServiceUnavailable(writer)
+		return
+	}
+
+	pv, found, err := h.service.getDryRunDiff()
+	if err != nil {
+		writer.Header().Add("Content-Type", "application/json")
+		writeJSONMessageWithStatus(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		writer.Header().Add("Content-Type", "application/json")
+		writeJSONMessageWithStatus(writer, "No dry run", http.StatusNotFound)
+		return
+	}
+	defer pv.Close()
+	writer.Header().Add("Content-Type", "application/x-ndjson")
+	writer.WriteHeader(http.StatusOK)
+	io.Copy(writer, &pv)
+}
+
+// RetryFailedPages - Fetch again the TME pages which failed during the last reload
+func (h *AuthorHandler) RetryFailedPages(writer http.ResponseWriter, req *http.Request) {
+	if !h.service.isInitialised() || !h.service.isDataLoaded() {
+		writeStatusServiceUnavailable(writer)
+		return
+	}
+
+	go func() {
+		if err := h.service.retryFailedPages(); err != nil {
+			log.Errorf("ERROR retrying failed TME pages: %v", err.Error())
+		}
+	}()
+	writeJSONMessageWithStatus(writer, "Retrying failed TME pages", http.StatusAccepted)
+}
+
+// GetGenerations - Return the generations of authors in the cache, the most recent first
+func (h *AuthorHandler) GetGenerations(writer http.ResponseWriter, req *http.Request) {
+	writer.Header().Add("Content-Type", "application/json")
+	if !h.service.isInitialised() {
+		writeStatusServiceUnavailable(writer)
+		return
+	}
+
+	generations, err := h.service.getGenerations()
+	if err != nil {
+		writeJSONMessageWithStatus(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSONResponse(generations, true, writer)
+}
+
+// GetDiff - Return how each author of a generation differs from an older generation, as NDJSON
+func (h *AuthorHandler) GetDiff(writer http.ResponseWriter, req *http.Request) {
+	if !h.service.isInitialised() {
+		writer.Header().Add("Content-Type", "application/json")
+		writeStatusServiceUnavailable(writer)
+		return
+	}
+	from := req.URL.Query().Get("from")
+	if from == "" {
+		writer.Header().Add("Content-Type", "application/json")
+		writeJSONMessageWithStatus(writer, "The generation to diff from is missing", http.StatusBadRequest)
+		return
+	}
+
+	pv, found, err := h.service.getDiff(from, req.URL.Query().Get("to"))
+	if err != nil {
+		writer.Header().Add("Content-Type", "application/json")
+		writeJSONMessageWithStatus(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		writer.Header().Add("Content-Type", "application/json")
+		writeJSONMessageWithStatus(writer, "Generation not found", http.StatusNotFound)
+		return
+	}
+	defer pv.Close()
+	writer.Header().Add("Content-Type", "application/x-ndjson")
+	writer.WriteHeader(http.StatusOK)
+	io.Copy(writer, &pv)
+}
+
+// ActivateBlockedReload - Activate the authors of a reload blocked by the reload guard
+func (h *AuthorHandler) ActivateBlockedReload(writer http.ResponseWriter, req *http.Request) {
+	writer.Header().Add("Content-Type", "application/json")
+	if !h.service.isInitialised() {
+		writeStatusServiceUnavailable(writer)
+		return
+	}
+
+	g, found, err := h.service.activateBlockedGeneration()
+	if err != nil {
+		writeJSONMessageWithStatus(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		writeJSONMessageWithStatus(writer, "No blocked reload", http.StatusNotFound)
+		return
+	}
+	writeJSONResponse(g, true, writer)
+}
+
+// GetSnapshot - Return a gzipped tar snapshot of the active authors, to seed the cache of another instance
+func (h *AuthorHandler) GetSnapshot(writer http.ResponseWriter, req *http.Request) {
+	if !h.service.isInitialised() {
+		writer.Header().Add("Content-Type", "application/json")
+		writeStatusServiceUnavailable(writer)
+		return
+	}
+
+	pv, id, err := h.service.getSnapshot()
+	if err != nil {
+		writer.Header().Add("Content-Type", "application/json")
+		writeJSONMessageWithStatus(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if id == "" {
+		writer.Header().Add("Content-Type", "application/json")
+		writeJSONMessageWithStatus(writer, "No authors to snapshot", http.StatusNotFound)
+		return
+	}
+	defer pv.Close()
+	writer.Header().Add("Content-Type", "application/gzip")
+	writer.Header().Add("Content-Disposition", fmt.Sprintf("attachment; filename=\"authors-%s.tar.gz\"", id))
+	writer.WriteHeader(http.StatusOK)
+	io.Copy(writer, &pv)
+}
+
 func writeJSONResponse(obj interface{}, found bool, writer http.ResponseWriter) {
 	if !found {
 		writeJSONMessageWithStatus(writer, "Author not found", http.StatusNotFound)
@@ -8,6 +8,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"regexp"
 	"strings"
 	"sync"
@@ -32,7 +33,15 @@ const (
 	getAuthorUUIDsResponse = `{"ID":"bba39990-c78d-3629-ae83-808c333c6dbc"}
 {"ID":"be2e7e2b-0fa2-3969-a69b-74c46e754032"}
 `
-	getAuthorByUUIDResponse = "{\"uuid\":\"bba39990-c78d-3629-ae83-808c333c6dbc\",\"prefLabel\":\"Julian Glover\",\"type\":\"Person\",\"alternativeIdentifiers\":{\"TME\":[\"MTE3-U3ViamVjdHM=\"],\"uuids\":[\"bba39990-c78d-3629-ae83-808c333c6dbc\"]},\"name\":\"Julian Glover\"}\n"
+	getUnmatchedResponse        = "[{\"name\":\"Julian Glover\",\"tmeIdentifier\":\"MTE3-U3ViamVjdHM=\",\"uuid\":\"bba39990-c78d-3629-ae83-808c333c6dbc\",\"closestMatches\":[{\"uuid\":\"be2e7e2b-0fa2-3969-a69b-74c46e754032\",\"prefLabel\":\"Julian Grover\",\"score\":0.92}]}]\n"
+	getDuplicatesResponse       = "[{\"score\":0.92,\"matchedOn\":[\"similarName\"],\"authors\":[{\"uuid\":\"bba39990-c78d-3629-ae83-808c333c6dbc\",\"prefLabel\":\"Julian Glover\",\"tmeIdentifier\":\"MTE3-U3ViamVjdHM=\"},{\"uuid\":\"be2e7e2b-0fa2-3969-a69b-74c46e754032\",\"prefLabel\":\"Julian Grover\"}]}]\n"
+	checkUUIDsResponse          = "[{\"uuid\":\"bba39990-c78d-3629-ae83-808c333c6dbc\",\"prefLabel\":\"Julian Glover\",\"tmeIdentifier\":\"MTE3-U3ViamVjdHM=\",\"expectedUuid\":\"be2e7e2b-0fa2-3969-a69b-74c46e754032\"}]\n"
+	getAuthorSourceResponse     = "{\"uuid\":\"bba39990-c78d-3629-ae83-808c333c6dbc\",\"tme\":[{\"tmeIdentifier\":\"MTE3-U3ViamVjdHM=\",\"taxonomy\":\"Authors\",\"xml\":\"\\u003cterm\\u003e\\u003cname\\u003eJulian Glover\\u003c/name\\u003e\\u003cid\\u003e117\\u003c/id\\u003e\\u003c/term\\u003e\"}],\"bertha\":{\"name\":\"Julian Glover\",\"tmeidentifier\":\"MTE3-U3ViamVjdHM=\"}}\n"
+	getReloadReportResponse     = "{\"startedAt\":\"2017-12-01T10:00:00Z\",\"descriptions\":[{\"uuid\":\"bba39990-c78d-3629-ae83-808c333c6dbc\",\"tmeIdentifier\":\"MTE3-U3ViamVjdHM=\",\"name\":\"Julian Glover\",\"changes\":[\"removed \\u003cscript\\u003e with its content\"]}],\"socialProfiles\":[{\"uuid\":\"bba39990-c78d-3629-ae83-808c333c6dbc\",\"tmeIdentifier\":\"MTE3-U3ViamVjdHM=\",\"name\":\"Julian Glover\",\"network\":\"twitter\",\"value\":\"@julian glover\",\"error\":\"@julian glover is not a valid Twitter handle\"}],\"images\":[{\"uuid\":\"bba39990-c78d-3629-ae83-808c333c6dbc\",\"tmeIdentifier\":\"MTE3-U3ViamVjdHM=\",\"name\":\"Julian Glover\",\"imageUrl\":\"julian.jpg\",\"error\":\"julian.jpg is not an absolute URL\"}],\"failedPages\":[{\"taxonomy\":\"Authors\",\"offset\":1000,\"error\":\"TME timed out\"}]}\n"
+	getAuthorProvenanceResponse = "{\"uuid\":\"bba39990-c78d-3629-ae83-808c333c6dbc\",\"fields\":{\"name\":{\"source\":\"Bertha\",\"sourceId\":\"MTE3-U3ViamVjdHM=\",\"sourceRow\":3,\"loadedAt\":\"2017-12-01T10:00:00Z\"}}}\n"
+	overrideRequestBody         = `{"fields": {"name": "Julian Glover"}, "user": "jane.doe", "reason": "typo"}`
+	putOverrideResponse         = "{\"uuid\":\"bba39990-c78d-3629-ae83-808c333c6dbc\",\"fields\":{\"name\":\"Julian Glover\"},\"user\":\"jane.doe\",\"reason\":\"typo\",\"updatedAt\":\"2017-12-01T10:00:00Z\"}\n"
+	getAuthorByUUIDResponse     = "{\"uuid\":\"bba39990-c78d-3629-ae83-808c333c6dbc\",\"prefLabel\":\"Julian Glover\",\"type\":\"Person\",\"alternativeIdentifiers\":{\"TME\":[\"MTE3-U3ViamVjdHM=\"],\"uuids\":[\"bba39990-c78d-3629-ae83-808c333c6dbc\"]},\"name\":\"Julian Glover\"}\n"
 )
 
 func TestHandlers(t *testing.T) {
@@ -54,6 +63,16 @@ func TestHandlers(t *testing.T) {
 			http.StatusOK,
 			"application/json",
 			getAuthorByUUIDResponse},
+		{"Moved permanently - get merged author by uuid",
+			newRequest("GET", fmt.Sprintf("/transformers/authors/%s", testUUID2)),
+			&dummyService{
+				found:       false,
+				initialised: true,
+				authors:     []author{{}},
+				redirects:   map[string]string{testUUID2: testUUID}},
+			http.StatusMovedPermanently,
+			"application/json",
+			"{\"message\": \"Author merged into bba39990-c78d-3629-ae83-808c333c6dbc\"}\n"},
 		{"Not found - get author by uuid",
 			newRequest("GET", fmt.Sprintf("/transformers/authors/%s", testUUID)),
 			&dummyService{
@@ -72,6 +91,146 @@ func TestHandlers(t *testing.T) {
 			http.StatusServiceUnavailable,
 			"application/json",
 			"{\"message\": \"Service Unavailable\"}\n"},
+		{"Success - get author provenance",
+			newRequest("GET", fmt.Sprintf("/transformers/authors/%s/__provenance", testUUID)),
+			&dummyService{
+				found:       true,
+				initialised: true,
+				provenance: authorProvenance{UUID: testUUID, Fields: map[string]fieldProvenance{
+					"name": {Source: "Bertha", SourceID: "MTE3-U3ViamVjdHM=", SourceRow: 3, LoadedAt: time.Date(2017, 12, 1, 10, 0, 0, 0, time.UTC)}}}},
+			http.StatusOK,
+			"application/json",
+			getAuthorProvenanceResponse},
+		{"Not found - get author provenance",
+			newRequest("GET", fmt.Sprintf("/transformers/authors/%s/__provenance", testUUID)),
+			&dummyService{
+				found:       false,
+				initialised: true},
+			http.StatusNotFound,
+			"application/json",
+			"{\"message\": \"Author not found\"}\n"},
+		{"Failure - get author provenance",
+			newRequest("GET", fmt.Sprintf("/transformers/authors/%s/__provenance", testUUID)),
+			&dummyService{
+				err:         errors.New("Something broke"),
+				initialised: true},
+			http.StatusInternalServerError,
+			"application/json",
+			"{\"message\": \"Something broke\"}\n"},
+		{"Success - get author source",
+			newRequest("GET", fmt.Sprintf("/transformers/authors/%s/__source", testUUID)),
+			&dummyService{
+				found:       true,
+				initialised: true,
+				source: authorSource{UUID: testUUID, TME: []tmeSource{{TmeIdentifier: "MTE3-U3ViamVjdHM=", Taxonomy: "Authors", XML: "<term><name>Julian Glover</name><id>117</id></term>"}},
+					Bertha: []byte(`{"name":"Julian Glover","tmeidentifier":"MTE3-U3ViamVjdHM="}`)}},
+			http.StatusOK,
+			"application/json",
+			getAuthorSourceResponse},
+		{"Not found - get author source",
+			newRequest("GET", fmt.Sprintf("/transformers/authors/%s/__source", testUUID)),
+			&dummyService{
+				found:       false,
+				initialised: true},
+			http.StatusNotFound,
+			"application/json",
+			"{\"message\": \"Author not found\"}\n"},
+		{"Failure - get author source",
+			newRequest("GET", fmt.Sprintf("/transformers/authors/%s/__source", testUUID)),
+			&dummyService{
+				err:         errors.New("Something broke"),
+				initialised: true},
+			http.StatusInternalServerError,
+			"application/json",
+			"{\"message\": \"Something broke\"}\n"},
+		{"Success - put override",
+			newRequestWithBody("PUT", fmt.Sprintf("/transformers/authors/%s/__override", testUUID), overrideRequestBody),
+			&dummyService{
+				found:       true,
+				initialised: true},
+			http.StatusOK,
+			"application/json",
+			putOverrideResponse},
+		{"Success - patch override",
+			newRequestWithBody("PATCH", fmt.Sprintf("/transformers/authors/%s/__override", testUUID), overrideRequestBody),
+			&dummyService{
+				found:       true,
+				initialised: true},
+			http.StatusOK,
+			"application/json",
+			putOverrideResponse},
+		{"Not found - put override",
+			newRequestWithBody("PUT", fmt.Sprintf("/transformers/authors/%s/__override", testUUID), overrideRequestBody),
+			&dummyService{
+				found:       false,
+				initialised: true},
+			http.StatusNotFound,
+			"application/json",
+			"{\"message\": \"Author not found\"}\n"},
+		{"Bad request - put override without reason",
+			newRequestWithBody("PUT", fmt.Sprintf("/transformers/authors/%s/__override", testUUID), `{"fields": {"name": "Julian Glover"}, "user": "jane.doe"}`),
+			&dummyService{
+				found:       true,
+				initialised: true},
+			http.StatusBadRequest,
+			"application/json",
+			"{\"message\": \"Invalid override: reason is mandatory\"}\n"},
+		{"Bad request - patch override of an unknown field",
+			newRequestWithBody("PATCH", fmt.Sprintf("/transformers/authors/%s/__override", testUUID), `{"fields": {"uuid": "x"}, "user": "jane.doe", "reason": "typo"}`),
+			&dummyService{
+				found:       true,
+				initialised: true},
+			http.StatusBadRequest,
+			"application/json",
+			"{\"message\": \"Invalid override: uuid cannot be overridden\"}\n"},
+		{"Success - patch override of a description which needs sanitising",
+			newRequestWithBody("PATCH", fmt.Sprintf("/transformers/authors/%s/__override", testUUID), `{"fields": {"descriptionXML": "<p onclick=\"steal()\">Bio</p><script>steal()</script>"}, "user": "jane.doe", "reason": "typo"}`),
+			&dummyService{
+				found:       true,
+				initialised: true},
+			http.StatusOK,
+			"application/json",
+			"{\"uuid\":\"bba39990-c78d-3629-ae83-808c333c6dbc\",\"fields\":{\"descriptionXML\":\"\\u003cp\\u003eBio\\u003c/p\\u003e\"},\"user\":\"jane.doe\",\"reason\":\"typo\",\"updatedAt\":\"2017-12-01T10:00:00Z\"}\n"},
+		{"Bad request - put override of an image on another host",
+			newRequestWithBody("PUT", fmt.Sprintf("/transformers/authors/%s/__override", testUUID), `{"fields": {"_imageUrl": "https://example.com/wolf.jpg"}, "user": "jane.doe", "reason": "New photo"}`),
+			&dummyService{
+				found:       true,
+				initialised: true},
+			http.StatusBadRequest,
+			"application/json",
+			"{\"message\": \"Invalid override: _imageUrl is not valid: https://example.com/wolf.jpg is not on an allowed image host\"}\n"},
+		{"Bad request - put override which is not JSON",
+			newRequestWithBody("PUT", fmt.Sprintf("/transformers/authors/%s/__override", testUUID), `name=Julian`),
+			&dummyService{
+				found:       true,
+				initialised: true},
+			http.StatusBadRequest,
+			"application/json",
+			"{\"message\": \"Invalid override: invalid character 'a' in literal null (expecting 'u')\"}\n"},
+		{"Success - delete override",
+			newRequest("DELETE", fmt.Sprintf("/transformers/authors/%s/__override", testUUID)),
+			&dummyService{
+				found:       true,
+				initialised: true},
+			http.StatusOK,
+			"application/json",
+			"{\"message\": \"Override removed, the author was restored from its sources\"}\n"},
+		{"Not found - delete override",
+			newRequest("DELETE", fmt.Sprintf("/transformers/authors/%s/__override", testUUID)),
+			&dummyService{
+				found:       false,
+				initialised: true},
+			http.StatusNotFound,
+			"application/json",
+			"{\"message\": \"Override not found\"}\n"},
+		{"Success - get overrides",
+			newRequest("GET", "/transformers/authors/__overrides"),
+			&dummyService{
+				initialised: true,
+				overrides:   []authorOverride{{UUID: testUUID, Fields: map[string]string{"name": "Julian Glover"}, User: "jane.doe", Reason: "typo", UpdatedAt: time.Date(2017, 12, 1, 10, 0, 0, 0, time.UTC)}}},
+			http.StatusOK,
+			"application/json",
+			"[" + putOverrideResponse[:len(putOverrideResponse)-1] + "]\n"},
 		{"Success - get authors count",
 			newRequest("GET", "/transformers/authors/__count"),
 			&dummyService{
@@ -210,6 +369,212 @@ func TestHandlers(t *testing.T) {
 			http.StatusOK,
 			"application/json",
 			"regex=Service is up and running"},
+		{"Health warning - unmatched curated authors",
+			newRequest("GET", "/__health"),
+			&dummyService{
+				initialised: true,
+				unmatched:   []unmatchedCuratedAuthor{{Name: "Julian Glover", UUID: testUUID}}},
+			http.StatusOK,
+			"application/json",
+			"regex=1 curated authors do not match any TME author"},
+		{"Health warning - blocked reload",
+			newRequest("GET", "/__health"),
+			&dummyService{
+				initialised: true,
+				blocked:     &generation{ID: "00000002", Status: "blocked", BlockedReasons: []string{"12 authors instead of 9000"}}},
+			http.StatusOK,
+			"application/json",
+			"regex=Generation 00000002 was not activated: 12 authors instead of 9000"},
+		{"Success - activate blocked reload",
+			newRequest("POST", "/transformers/authors/__reload/activate"),
+			&dummyService{
+				initialised: true,
+				blocked:     &generation{ID: "00000002", Status: "active", StartedAt: time.Date(2017, 12, 1, 10, 0, 0, 0, time.UTC), Count: 12}},
+			http.StatusOK,
+			"application/json",
+			"{\"id\":\"00000002\",\"status\":\"active\",\"startedAt\":\"2017-12-01T10:00:00Z\",\"count\":12,\"curatedMatches\":0}\n"},
+		{"Success - dry run reload",
+			newRequest("POST", "/transformers/authors/__reload?dryRun=true"),
+			&dummyService{
+				initialised: true,
+				dataLoaded:  true,
+				summary:     diffSummary{From: "00000001", To: "00000002", Added: []string{testUUID}, Removed: []string{}, Changed: map[string][]string{testUUID2: {"aliases", "prefLabel"}}}},
+			http.StatusOK,
+			"application/json",
+			"{\"from\":\"00000001\",\"to\":\"00000002\",\"added\":[\"bba39990-c78d-3629-ae83-808c333c6dbc\"],\"removed\":[],\"changed\":{\"be2e7e2b-0fa2-3969-a69b-74c46e754032\":[\"aliases\",\"prefLabel\"]}}\n"},
+		{"Dry run reload - Service unavailable as data not loaded",
+			newRequest("POST", "/transformers/authors/__reload?dryRun=true"),
+			&dummyService{
+				initialised: true,
+				dataLoaded:  false},
+			http.StatusServiceUnavailable,
+			"application/json",
+			"{\"message\": \"Service Unavailable\"}\n"},
+		{"Success - get dry run diff",
+			newRequest("GET", "/transformers/authors/__reload/diff"),
+			&dummyService{
+				initialised: true,
+				diffs:       []authorDiff{{UUID: testUUID2, PrefLabel: "Julian Glover", Change: "changed", Fields: []fieldChange{{Field: "prefLabel", From: "Julian Grover", To: "Julian Glover"}}}}},
+			http.StatusOK,
+			"application/x-ndjson",
+			"{\"uuid\":\"be2e7e2b-0fa2-3969-a69b-74c46e754032\",\"prefLabel\":\"Julian Glover\",\"change\":\"changed\",\"fields\":[{\"field\":\"prefLabel\",\"from\":\"Julian Grover\",\"to\":\"Julian Glover\"}]}\n"},
+		{"Not found - no dry run diff",
+			newRequest("GET", "/transformers/authors/__reload/diff"),
+			&dummyService{
+				initialised: true},
+			http.StatusNotFound,
+			"application/json",
+			"{\"message\": \"No dry run\"}\n"},
+		{"Success - get generations",
+			newRequest("GET", "/transformers/authors/__generations"),
+			&dummyService{
+				initialised: true,
+				generations: []generation{{ID: "00000002", Status: "active", StartedAt: time.Date(2017, 12, 8, 10, 0, 0, 0, time.UTC), Count: 9871, CuratedMatches: 157},
+					{ID: "00000001", Status: "retired", StartedAt: time.Date(2017, 12, 1, 10, 0, 0, 0, time.UTC), Count: 9870, CuratedMatches: 156}}},
+			http.StatusOK,
+			"application/json",
+			"[{\"id\":\"00000002\",\"status\":\"active\",\"startedAt\":\"2017-12-08T10:00:00Z\",\"count\":9871,\"curatedMatches\":157},{\"id\":\"00000001\",\"status\":\"retired\",\"startedAt\":\"2017-12-01T10:00:00Z\",\"count\":9870,\"curatedMatches\":156}]\n"},
+		{"Success - diff generations",
+			newRequest("GET", "/transformers/authors/__diff?from=00000001&to=00000002"),
+			&dummyService{
+				initialised: true,
+				diffs:       []authorDiff{{UUID: testUUID, PrefLabel: "Julian Glover", Change: "added", Fields: []fieldChange{{Field: "prefLabel", To: "Julian Glover"}}}}},
+			http.StatusOK,
+			"application/x-ndjson",
+			"{\"uuid\":\"bba39990-c78d-3629-ae83-808c333c6dbc\",\"prefLabel\":\"Julian Glover\",\"change\":\"added\",\"fields\":[{\"field\":\"prefLabel\",\"to\":\"Julian Glover\"}]}\n"},
+		{"Bad request - diff without generation to diff from",
+			newRequest("GET", "/transformers/authors/__diff"),
+			&dummyService{
+				initialised: true},
+			http.StatusBadRequest,
+			"application/json",
+			"{\"message\": \"The generation to diff from is missing\"}\n"},
+		{"Not found - diff unknown generation",
+			newRequest("GET", "/transformers/authors/__diff?from=00000001"),
+			&dummyService{
+				initialised: true},
+			http.StatusNotFound,
+			"application/json",
+			"{\"message\": \"Generation not found\"}\n"},
+		{"Success - get snapshot",
+			newRequest("GET", "/transformers/authors/__snapshot"),
+			&dummyService{
+				initialised: true,
+				snapshot:    "snapshot"},
+			http.StatusOK,
+			"application/gzip",
+			"snapshot"},
+		{"Not found - no authors to snapshot",
+			newRequest("GET", "/transformers/authors/__snapshot"),
+			&dummyService{
+				initialised: true},
+			http.StatusNotFound,
+			"application/json",
+			"{\"message\": \"No authors to snapshot\"}\n"},
+		{"Not found - no blocked reload to activate",
+			newRequest("POST", "/transformers/authors/__reload/activate"),
+			&dummyService{
+				initialised: true},
+			http.StatusNotFound,
+			"application/json",
+			"{\"message\": \"No blocked reload\"}\n"},
+		{"Success - get unmatched curated authors",
+			newRequest("GET", "/transformers/authors/__unmatched"),
+			&dummyService{
+				initialised: true,
+				unmatched: []unmatchedCuratedAuthor{{Name: "Julian Glover", TmeIdentifier: "MTE3-U3ViamVjdHM=", UUID: testUUID,
+					ClosestMatches: []authorMatch{{UUID: testUUID2, PrefLabel: "Julian Grover", Score: 0.92}}}}},
+			http.StatusOK,
+			"application/json",
+			getUnmatchedResponse},
+		{"Success - no unmatched curated authors",
+			newRequest("GET", "/transformers/authors/__unmatched"),
+			&dummyService{
+				initialised: true},
+			http.StatusOK,
+			"application/json",
+			"[]\n"},
+		{"Service unavailable - get unmatched curated authors",
+			newRequest("GET", "/transformers/authors/__unmatched"),
+			&dummyService{
+				initialised: false},
+			http.StatusServiceUnavailable,
+			"application/json",
+			"{\"message\": \"Service Unavailable\"}\n"},
+		{"Success - get duplicates",
+			newRequest("GET", "/transformers/authors/__duplicates"),
+			&dummyService{
+				initialised: true,
+				dataLoaded:  true,
+				duplicates: []duplicateGroup{{Score: 0.92, MatchedOn: []string{"similarName"}, Authors: []duplicateAuthor{
+					{UUID: testUUID, PrefLabel: "Julian Glover", TmeIdentifier: "MTE3-U3ViamVjdHM="},
+					{UUID: testUUID2, PrefLabel: "Julian Grover"}}}}},
+			http.StatusOK,
+			"application/json",
+			getDuplicatesResponse},
+		{"Success - no duplicates",
+			newRequest("GET", "/transformers/authors/__duplicates"),
+			&dummyService{
+				initialised: true,
+				dataLoaded:  true},
+			http.StatusOK,
+			"application/json",
+			"[]\n"},
+		{"Service unavailable - get duplicates while loading",
+			newRequest("GET", "/transformers/authors/__duplicates"),
+			&dummyService{
+				initialised: true,
+				dataLoaded:  false},
+			http.StatusServiceUnavailable,
+			"application/json",
+			"{\"message\": \"Service Unavailable\"}\n"},
+		{"Success - check UUIDs",
+			newRequest("GET", "/transformers/authors/__uuids/check"),
+			&dummyService{
+				initialised: true,
+				dataLoaded:  true,
+				mismatches:  []uuidMismatch{{UUID: testUUID, PrefLabel: "Julian Glover", TmeIdentifier: "MTE3-U3ViamVjdHM=", ExpectedUUID: testUUID2}}},
+			http.StatusOK,
+			"application/json",
+			checkUUIDsResponse},
+		{"Error - check UUIDs",
+			newRequest("GET", "/transformers/authors/__uuids/check"),
+			&dummyService{
+				initialised: true,
+				dataLoaded:  true,
+				err:         errors.New("Something broke")},
+			http.StatusInternalServerError,
+			"application/json",
+			"{\"message\": \"Something broke\"}\n"},
+		{"Service unavailable - check UUIDs while loading",
+			newRequest("GET", "/transformers/authors/__uuids/check"),
+			&dummyService{
+				initialised: true,
+				dataLoaded:  false},
+			http.StatusServiceUnavailable,
+			"application/json",
+			"{\"message\": \"Service Unavailable\"}\n"},
+		{"Success - get reload report",
+			newRequest("GET", "/transformers/authors/__report"),
+			&dummyService{
+				initialised: true,
+				report: reloadReport{StartedAt: time.Date(2017, 12, 1, 10, 0, 0, 0, time.UTC), Descriptions: []descriptionReport{
+					{UUID: testUUID, TmeIdentifier: "MTE3-U3ViamVjdHM=", Name: "Julian Glover", Changes: []string{"removed <script> with its content"}}},
+					SocialProfiles: []socialProfileReport{{UUID: testUUID, TmeIdentifier: "MTE3-U3ViamVjdHM=", Name: "Julian Glover", Network: "twitter",
+						Value: "@julian glover", Error: "@julian glover is not a valid Twitter handle"}},
+					Images: []imageReport{{UUID: testUUID, TmeIdentifier: "MTE3-U3ViamVjdHM=", Name: "Julian Glover", ImageURL: "julian.jpg",
+						Error: "julian.jpg is not an absolute URL"}},
+					FailedPages: []failedPage{{Taxonomy: "Authors", Offset: 1000, Error: "TME timed out"}}}},
+			http.StatusOK,
+			"application/json",
+			getReloadReportResponse},
+		{"Service unavailable - get reload report",
+			newRequest("GET", "/transformers/authors/__report"),
+			&dummyService{
+				initialised: false},
+			http.StatusServiceUnavailable,
+			"application/json",
+			"{\"message\": \"Service Unavailable\"}\n"},
 		{"Reload accepted - request reload",
 			newRequest("POST", "/transformers/authors/__reload"),
 			&dummyService{
@@ -249,6 +614,24 @@ func TestHandlers(t *testing.T) {
 			http.StatusServiceUnavailable,
 			"application/json",
 			"{\"message\": \"Service Unavailable\"}\n"},
+		{"Retry failed pages accepted",
+			newRequest("POST", "/transformers/authors/__reload/failed"),
+			&dummyService{
+				wg:          &wg,
+				initialised: true,
+				dataLoaded:  true},
+			http.StatusAccepted,
+			"application/json",
+			"{\"message\": \"Retrying failed TME pages\"}\n"},
+		{"Retry failed pages - Service unavailable as data not loaded",
+			newRequest("POST", "/transformers/authors/__reload/failed"),
+			&dummyService{
+				wg:          &wg,
+				initialised: true,
+				dataLoaded:  false},
+			http.StatusServiceUnavailable,
+			"application/json",
+			"{\"message\": \"Service Unavailable\"}\n"},
 	}
 	for _, test := range tests {
 		wg.Add(1)
@@ -270,6 +653,74 @@ func TestHandlers(t *testing.T) {
 	}
 }
 
+func TestGetMergedAuthorRedirectsToCanonicalAuthor(t *testing.T) {
+	rec := httptest.NewRecorder()
+	service := &dummyService{initialised: true, authors: []author{{}}, redirects: map[string]string{testUUID2: testUUID}}
+	router(service).ServeHTTP(rec, newRequest("GET", fmt.Sprintf("/transformers/authors/%s", testUUID2)))
+	assert.Equal(t, http.StatusMovedPermanently, rec.Code)
+	assert.Equal(t, fmt.Sprintf("/transformers/authors/%s", testUUID), rec.Header().Get("Location"))
+}
+
+func TestGetAuthorDuringFirstLoadIsNotFound(t *testing.T) {
+	repo := blockingRepo{}
+	repo.Add(1)
+	tmpfile := getTempFile(t)
+	defer os.Remove(tmpfile.Name())
+	service := createTestAuthorService(&repo, tmpfile.Name())
+	defer func() {
+		repo.Done()
+		service.Shutdown()
+	}()
+	waitTillInit(t, service)
+	for i := 1; i <= 1000 && service.getReloadReport().StartedAt.IsZero(); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	for _, path := range []string{"/transformers/authors/%s", "/transformers/authors/%s/__provenance", "/transformers/authors/%s/__source"} {
+		rec := httptest.NewRecorder()
+		router(service).ServeHTTP(rec, newRequest("GET", fmt.Sprintf(path, testUUID)))
+		assert.Equal(t, http.StatusNotFound, rec.Code, path)
+		assert.Equal(t, "{\"message\": \"Author not found\"}\n", rec.Body.String(), path)
+	}
+
+	rec := httptest.NewRecorder()
+	router(service).ServeHTTP(rec, newRequestWithBody("PATCH", fmt.Sprintf("/transformers/authors/%s/__override", testUUID), overrideRequestBody))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "{\"message\": \"Author not found\"}\n", rec.Body.String())
+}
+
+func TestGetAuthorFromExpiredCacheIsNotFound(t *testing.T) {
+	tmpfile := getTempFile(t)
+	defer os.Remove(tmpfile.Name())
+	repo := dummyRepo{terms: []term{{CanonicalName: "Bob", RawID: "bob", Raw: "<name>Bob</name><id>bob</id>"}}}
+	service := createTestAuthorService(&repo, tmpfile.Name())
+	waitTillInit(t, service)
+	waitTillDataLoaded(t, service)
+	assert.NoError(t, service.Shutdown())
+
+	slowRepo := blockingRepo{}
+	slowRepo.Add(1)
+	restarted := createStartupTestService(&slowRepo, tmpfile.Name(), ReloadGuard{}, StartupOptions{MaxCacheAge: time.Nanosecond})
+	defer func() {
+		slowRepo.Done()
+		restarted.Shutdown()
+	}()
+	waitTillInit(t, restarted)
+	for i := 1; i <= 1000 && restarted.getReloadReport().StartedAt.IsZero(); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	bobUUID := UUIDStrategy{}.uuidOf(buildTmeIdentifier("bob", "taxonomy_string"))
+	for _, path := range []string{"/transformers/authors/%s", "/transformers/authors/%s/__provenance", "/transformers/authors/%s/__source"} {
+		rec := httptest.NewRecorder()
+		router(restarted).ServeHTTP(rec, newRequest("GET", fmt.Sprintf(path, bobUUID)))
+		assert.Equal(t, http.StatusNotFound, rec.Code, "%v should not serve the expired cache", path)
+	}
+	_, found, err := restarted.getCanonicalUUID(bobUUID)
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
+
 func TestReloadIsCalled(t *testing.T) {
 	var wg sync.WaitGroup
 	wg.Add(1)
@@ -295,6 +746,14 @@ func newRequest(method, url string) *http.Request {
 	return req
 }
 
+func newRequestWithBody(method, url, body string) *http.Request {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		panic(err)
+	}
+	return req
+}
+
 type dummyService struct {
 	found        bool
 	authors      []author
@@ -304,12 +763,66 @@ type dummyService struct {
 	err          error
 	loadDBCalled bool
 	wg           *sync.WaitGroup
+	unmatched    []unmatchedCuratedAuthor
+	duplicates   []duplicateGroup
+	mismatches   []uuidMismatch
+	redirects    map[string]string
+	report       reloadReport
+	provenance   authorProvenance
+	source       authorSource
+	overrides    []authorOverride
+	blocked      *generation
+	summary      diffSummary
+	diffs        []authorDiff
+	generations  []generation
+	snapshot     string
 }
 
 func (s *dummyService) loadCuratedAuthors(bAuthors []berthaAuthor) error {
 	return nil
 }
 
+func (s *dummyService) getUnmatchedCuratedAuthors() []unmatchedCuratedAuthor {
+	return s.unmatched
+}
+
+func (s *dummyService) getReloadReport() reloadReport {
+	return s.report
+}
+
+func (s *dummyService) getDuplicates() []duplicateGroup {
+	return s.duplicates
+}
+
+func (s *dummyService) checkUUIDs() ([]uuidMismatch, error) {
+	return s.mismatches, s.err
+}
+
+func (s *dummyService) getAuthorProvenance(uuid string) (authorProvenance, bool, error) {
+	return s.provenance, s.found, s.err
+}
+
+func (s *dummyService) getAuthorSource(uuid string) (authorSource, bool, error) {
+	return s.source, s.found, s.err
+}
+
+func (s *dummyService) getOverrides() ([]authorOverride, error) {
+	return s.overrides, s.err
+}
+
+func (s *dummyService) normaliseOverride(req overrideRequest) (overrideRequest, error) {
+	return normaliseOverrideRequest(req, ImageOptions{})
+}
+
+func (s *dummyService) saveOverride(uuid string, req overrideRequest, replace bool) (authorOverride, bool, error) {
+	o := updateOverride(authorOverride{}, uuid, req, replace, time.Date(2017, 12, 1, 10, 0, 0, 0, time.UTC))
+	return o, s.found, s.err
+}
+
+func (s *dummyService) deleteOverride(uuid string) (bool, error) {
+	return s.found, s.err
+}
+
 func (s *dummyService) getAuthors() (io.PipeReader, error) {
 	pv, pw := io.Pipe()
 	go func() {
@@ -357,6 +870,11 @@ func (s *dummyService) getAuthorByUUID(uuid string) (author, bool, error) {
 	return s.authors[0], s.found, nil
 }
 
+func (s *dummyService) getCanonicalUUID(uuid string) (string, bool, error) {
+	canonicalUUID, ok := s.redirects[uuid]
+	return canonicalUUID, ok, nil
+}
+
 func (s *dummyService) isInitialised() bool {
 	return s.initialised
 }
@@ -375,20 +893,94 @@ func (s *dummyService) reloadDB() error {
 	return s.err
 }
 
+func (s *dummyService) retryFailedPages() error {
+	defer s.wg.Done()
+	return s.err
+}
+
+func (s *dummyService) dryRunReload() (diffSummary, error) {
+	return s.summary, s.err
+}
+
+func (s *dummyService) getDryRunDiff() (io.PipeReader, bool, error) {
+	if s.diffs == nil {
+		return io.PipeReader{}, false, s.err
+	}
+	pv, pw := io.Pipe()
+	go func() {
+		encoder := json.NewEncoder(pw)
+		for _, d := range s.diffs {
+			encoder.Encode(d)
+		}
+		pw.Close()
+	}()
+	return *pv, true, s.err
+}
+
+func (s *dummyService) getGenerations() ([]generation, error) {
+	return s.generations, s.err
+}
+
+func (s *dummyService) getDiff(from string, to string) (io.PipeReader, bool, error) {
+	return s.getDryRunDiff()
+}
+
+func (s *dummyService) getBlockedGeneration() (generation, bool) {
+	if s.blocked == nil {
+		return generation{}, false
+	}
+	return *s.blocked, true
+}
+
+func (s *dummyService) activateBlockedGeneration() (generation, bool, error) {
+	if s.blocked == nil {
+		return generation{}, false, s.err
+	}
+	return *s.blocked, true, s.err
+}
+
+func (s *dummyService) getSnapshot() (io.PipeReader, string, error) {
+	if s.snapshot == "" {
+		return io.PipeReader{}, "", s.err
+	}
+	pv, pw := io.Pipe()
+	go func() {
+		pw.Write([]byte(s.snapshot))
+		pw.Close()
+	}()
+	return *pv, "00000002", s.err
+}
+
 func router(s AuthorService) *mux.Router {
 	m := mux.NewRouter()
 	h := NewAuthorHandler(s)
 	m.HandleFunc("/transformers/authors", h.GetAuthors).Methods("GET")
 	m.HandleFunc("/transformers/authors/__count", h.GetCount).Methods("GET")
 	m.HandleFunc("/transformers/authors/__reload", h.Reload).Methods("POST")
+	m.HandleFunc("/transformers/authors/__reload/failed", h.RetryFailedPages).Methods("POST")
+	m.HandleFunc("/transformers/authors/__reload/activate", h.ActivateBlockedReload).Methods("POST")
+	m.HandleFunc("/transformers/authors/__reload/diff", h.GetDryRunDiff).Methods("GET")
+	m.HandleFunc("/transformers/authors/__generations", h.GetGenerations).Methods("GET")
+	m.HandleFunc("/transformers/authors/__diff", h.GetDiff).Methods("GET")
+	m.HandleFunc("/transformers/authors/__snapshot", h.GetSnapshot).Methods("GET")
 	m.HandleFunc("/transformers/authors/__id", h.GetAuthorUUIDs).Methods("GET")
+	m.HandleFunc("/transformers/authors/__unmatched", h.GetUnmatchedCuratedAuthors).Methods("GET")
+	m.HandleFunc("/transformers/authors/__duplicates", h.GetDuplicates).Methods("GET")
+	m.HandleFunc("/transformers/authors/__report", h.GetReloadReport).Methods("GET")
+	m.HandleFunc("/transformers/authors/__uuids/check", h.GetUUIDMismatches).Methods("GET")
+	m.HandleFunc("/transformers/authors/__overrides", h.GetOverrides).Methods("GET")
 	m.HandleFunc("/transformers/authors/{uuid}", h.GetAuthorByUUID).Methods("GET")
+	m.HandleFunc("/transformers/authors/{uuid}/__provenance", h.GetAuthorProvenance).Methods("GET")
+	m.HandleFunc("/transformers/authors/{uuid}/__source", h.GetAuthorSource).Methods("GET")
+	m.HandleFunc("/transformers/authors/{uuid}/__override", h.PutOverride).Methods("PUT")
+	m.HandleFunc("/transformers/authors/{uuid}/__override", h.PatchOverride).Methods("PATCH")
+	m.HandleFunc("/transformers/authors/{uuid}/__override", h.DeleteOverride).Methods("DELETE")
 	timedHC := fthealth.TimedHealthCheck{
 		HealthCheck: fthealth.HealthCheck{
 			SystemCode:  "v1-authors-tf",
 			Name:        "V1 Authors Transformer",
 			Description: "It pulls and transforms V1/TME Authors into the UPP JSON model of an Author.",
-			Checks:      []fthealth.Check{h.HealthCheck()},
+			Checks:      []fthealth.Check{h.HealthCheck(), h.UnmatchedCuratedAuthorsCheck(), h.BlockedReloadCheck(), h.CacheCompactionCheck()},
 		},
 		Timeout: 10 * time.Second,
 	}
@@ -0,0 +1,101 @@
+package authors
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// DefaultImageHosts are the hosts curated author images can be on when no other hosts are configured
+var DefaultImageHosts = []string{"www.ft.com", "im.ft-static.com", "image.webservices.ft.com"}
+
+const origamiImagePath = "/__origami/service/image/v2/images/raw/"
+
+// origamiSchemes are the Origami image sets which can be referenced without a URL, as in "fthead:martin-wolf"
+var origamiSchemes = []string{"fthead", "ftlogo", "ftsocial", "ftpodcast"}
+
+// ImageOptions - how the image URLs of curated authors are checked and rewritten
+type ImageOptions struct {
+	// AllowedHosts are the hosts an image can be on. The defaults are used when empty.
+	AllowedHosts []string
+	// Origami rewrites every image into an Origami image service URL
+	Origami bool
+	// Width is the width in pixels asked of the Origami image service, if any
+	Width int
+	// Format is the format asked of the Origami image service, if any, such as "auto" or "png"
+	Format string
+}
+
+func (o ImageOptions) allowedHost(host string) bool {
+	hosts := o.AllowedHosts
+	if len(hosts) == 0 {
+		hosts = DefaultImageHosts
+	}
+	return contains(hosts, strings.ToLower(host))
+}
+
+// normaliseImageURL checks that an image URL is absolute and on an allowed host and, when asked to, rewrites
+// it into an Origami image service URL. Origami image references are only accepted when rewriting.
+func normaliseImageURL(imageURL string, opts ImageOptions) (string, error) {
+	imageURL = strings.TrimSpace(imageURL)
+	if imageURL == "" {
+		return "", nil
+	}
+	if scheme := origamiScheme(imageURL); scheme != "" {
+		if !opts.Origami {
+			return "", fmt.Errorf("%v is an Origami image reference but Origami rewriting is off", imageURL)
+		}
+		return origamiURL(imageURL, opts), nil
+	}
+
+	u, err := url.Parse(imageURL)
+	if err != nil || !u.IsAbs() || u.Host == "" {
+		return "", fmt.Errorf("%v is not an absolute URL", imageURL)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("%v is not an http or https URL", imageURL)
+	}
+	if !opts.allowedHost(u.Host) {
+		return "", fmt.Errorf("%v is not on an allowed image host", imageURL)
+	}
+	if !opts.Origami {
+		return imageURL, nil
+	}
+	if strings.HasPrefix(u.Path, origamiImagePath) {
+		reference, err := url.QueryUnescape(strings.TrimPrefix(u.EscapedPath(), origamiImagePath))
+		if err != nil || reference == "" {
+			return "", fmt.Errorf("%v is not a valid Origami image URL", imageURL)
+		}
+		if origamiScheme(reference) == "" {
+			return normaliseImageURL(reference, opts)
+		}
+		return origamiURL(reference, opts), nil
+	}
+	return origamiURL(imageURL, opts), nil
+}
+
+// origamiScheme is the Origami image set of an image reference, if it is one
+func origamiScheme(reference string) string {
+	for _, scheme := range origamiSchemes {
+		if strings.HasPrefix(strings.ToLower(reference), scheme+":") {
+			return scheme
+		}
+	}
+	return ""
+}
+
+// origamiURL is the Origami image service URL of an image reference or of the URL of an image
+func origamiURL(reference string, opts ImageOptions) string {
+	if origamiScheme(reference) == "" {
+		reference = url.QueryEscape(reference)
+	}
+	params := url.Values{"source": {"next"}}
+	if opts.Width > 0 {
+		params.Set("width", strconv.Itoa(opts.Width))
+	}
+	if opts.Format != "" {
+		params.Set("format", opts.Format)
+	}
+	return ftBaseURL + origamiImagePath + reference + "?" + params.Encode()
+}
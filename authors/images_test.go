@@ -0,0 +1,49 @@
+package authors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormaliseImageURL(t *testing.T) {
+	origami := ImageOptions{Origami: true, Width: 150, Format: "auto"}
+	tests := []struct {
+		name     string
+		imageURL string
+		opts     ImageOptions
+		expected string
+		err      string
+	}{
+		{"Empty", "", ImageOptions{}, "", ""},
+		{"Allowed host", "https://im.ft-static.com/content/images/martin-wolf.jpg", ImageOptions{},
+			"https://im.ft-static.com/content/images/martin-wolf.jpg", ""},
+		{"Relative", "martin-wolf.jpg", ImageOptions{}, "", "martin-wolf.jpg is not an absolute URL"},
+		{"Not http", "ftp://www.ft.com/martin-wolf.jpg", ImageOptions{}, "", "ftp://www.ft.com/martin-wolf.jpg is not an http or https URL"},
+		{"Host not allowed", "https://example.com/martin-wolf.jpg", ImageOptions{}, "", "https://example.com/martin-wolf.jpg is not on an allowed image host"},
+		{"Configured host", "https://example.com/martin-wolf.jpg", ImageOptions{AllowedHosts: []string{"example.com"}},
+			"https://example.com/martin-wolf.jpg", ""},
+		{"Origami reference without rewriting", "fthead:martin-wolf", ImageOptions{}, "",
+			"fthead:martin-wolf is an Origami image reference but Origami rewriting is off"},
+		{"Origami reference", "fthead:martin-wolf", origami,
+			"https://www.ft.com/__origami/service/image/v2/images/raw/fthead:martin-wolf?format=auto&source=next&width=150", ""},
+		{"Image URL", "https://im.ft-static.com/content/images/martin-wolf.jpg", origami,
+			"https://www.ft.com/__origami/service/image/v2/images/raw/https%3A%2F%2Fim.ft-static.com%2Fcontent%2Fimages%2Fmartin-wolf.jpg?format=auto&source=next&width=150", ""},
+		{"Origami URL", "https://www.ft.com/__origami/service/image/v2/images/raw/fthead:martin-wolf?source=next", origami,
+			"https://www.ft.com/__origami/service/image/v2/images/raw/fthead:martin-wolf?format=auto&source=next&width=150", ""},
+		{"Origami URL of an image URL", "https://www.ft.com/__origami/service/image/v2/images/raw/https%3A%2F%2Fexample.com%2Fmartin-wolf.jpg", origami,
+			"", "https://example.com/martin-wolf.jpg is not on an allowed image host"},
+		{"Origami without parameters", "fthead:martin-wolf", ImageOptions{Origami: true},
+			"https://www.ft.com/__origami/service/image/v2/images/raw/fthead:martin-wolf?source=next", ""},
+	}
+
+	for _, test := range tests {
+		actual, err := normaliseImageURL(test.imageURL, test.opts)
+		if test.err != "" {
+			assert.EqualError(t, err, test.err, test.name)
+			continue
+		}
+		assert.NoError(t, err, test.name)
+		assert.Equal(t, test.expected, actual, test.name)
+	}
+}
@@ -0,0 +1,318 @@
+package authors
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+var errTxNotWritable = errors.New("tx not writable")
+
+// MemoryAuthorStore - an AuthorStore in memory, for small deployments and tests. Updates are serialised and
+// change a copy of the authors, which replaces them once the update succeeds, so views read the authors as they
+// were when they started without blocking the updates.
+type MemoryAuthorStore struct {
+	sync.RWMutex
+	writing sync.Mutex
+	state   *memoryState
+}
+
+// memoryState is what a memory store holds. Once it's stored it never changes: an update copies the buckets it
+// writes to.
+type memoryState struct {
+	buckets     map[string]*memoryBucket
+	generations map[string]map[string]*memoryBucket
+	sequence    uint64
+}
+
+type memoryBucket struct {
+	values map[string][]byte
+}
+
+// NewMemoryAuthorStore - store the authors in memory
+func NewMemoryAuthorStore() *MemoryAuthorStore {
+	return &MemoryAuthorStore{state: &memoryState{}}
+}
+
+// Open creates the top level buckets
+func (s *MemoryAuthorStore) Open() error {
+	s.writing.Lock()
+	defer s.writing.Unlock()
+	state := s.state.copy()
+	if state.buckets == nil {
+		state.buckets = make(map[string]*memoryBucket)
+		state.generations = make(map[string]map[string]*memoryBucket)
+	}
+	for _, name := range topLevelBuckets {
+		if state.buckets[name] == nil {
+			state.buckets[name] = &memoryBucket{values: make(map[string][]byte)}
+		}
+	}
+	s.store(state)
+	return nil
+}
+
+// View runs a function on the authors as they are when it starts
+func (s *MemoryAuthorStore) View(fn func(tx StoreTx) error) error {
+	s.RLock()
+	state := s.state
+	s.RUnlock()
+	return fn(&memoryTx{state: state})
+}
+
+// Update runs a function after the previous updates, keeping its changes only when it succeeds
+func (s *MemoryAuthorStore) Update(fn func(tx StoreTx) error) error {
+	s.writing.Lock()
+	defer s.writing.Unlock()
+	tx := &memoryTx{state: s.state.copy(), writable: true, copied: make(map[*memoryBucket]bool), copiedGenerations: make(map[string]bool)}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	s.store(tx.state)
+	return nil
+}
+
+func (s *MemoryAuthorStore) store(state *memoryState) {
+	s.Lock()
+	defer s.Unlock()
+	s.state = state
+}
+
+// Batch is Update, there's nothing to gain from batching updates in memory
+func (s *MemoryAuthorStore) Batch(fn func(tx StoreTx) error) error {
+	return s.Update(fn)
+}
+
+// Close keeps the authors, a closed memory store can be used again
+func (s *MemoryAuthorStore) Close() error {
+	return nil
+}
+
+// copy shares the buckets of the state, which the copy replaces with copies of their own before writing to them
+func (st *memoryState) copy() *memoryState {
+	if st.buckets == nil {
+		return &memoryState{}
+	}
+	c := &memoryState{
+		buckets:     make(map[string]*memoryBucket, len(st.buckets)),
+		generations: make(map[string]map[string]*memoryBucket, len(st.generations)),
+		sequence:    st.sequence,
+	}
+	for name, b := range st.buckets {
+		c.buckets[name] = b
+	}
+	for id, g := range st.generations {
+		c.generations[id] = g
+	}
+	return c
+}
+
+// memoryTx reads a state. An update also records which buckets and generations it copied, which are its own to
+// change.
+type memoryTx struct {
+	state             *memoryState
+	writable          bool
+	copied            map[*memoryBucket]bool
+	copiedGenerations map[string]bool
+}
+
+// bucket is a top level bucket when generation is empty, or a bucket of the generation
+func (t *memoryTx) bucket(generation string, name string) *memoryBucket {
+	if generation == "" {
+		return t.state.buckets[name]
+	}
+	return t.state.generations[generation][name]
+}
+
+// writableBucket copies a bucket the first time the update writes to it
+func (t *memoryTx) writableBucket(generation string, name string) *memoryBucket {
+	b := t.bucket(generation, name)
+	if t.copied[b] {
+		return b
+	}
+	c := &memoryBucket{values: make(map[string][]byte, len(b.values))}
+	for k, v := range b.values {
+		c.values[k] = v
+	}
+	if generation == "" {
+		t.state.buckets[name] = c
+	} else {
+		if !t.copiedGenerations[generation] {
+			g := make(map[string]*memoryBucket)
+			for n, gb := range t.state.generations[generation] {
+				g[n] = gb
+			}
+			t.state.generations[generation] = g
+			t.copiedGenerations[generation] = true
+		}
+		t.state.generations[generation][name] = c
+	}
+	t.copied[c] = true
+	return c
+}
+
+func (t *memoryTx) Bucket(name string) StoreBucket {
+	if t.bucket("", name) != nil {
+		return &memoryTxBucket{tx: t, name: name}
+	}
+	return nil
+}
+
+func (t *memoryTx) Generation(id string) StoreGeneration {
+	if _, ok := t.state.generations[id]; ok {
+		return &memoryGeneration{id: id, tx: t}
+	}
+	return nil
+}
+
+func (t *memoryTx) CreateGeneration(id string, buckets []string) (StoreGeneration, error) {
+	if !t.writable {
+		return nil, errTxNotWritable
+	}
+	if t.state.generations == nil {
+		return nil, errors.New("DB not open")
+	}
+	if _, ok := t.state.generations[id]; ok {
+		return nil, fmt.Errorf("Generation [%v] already exists!", id)
+	}
+	g := make(map[string]*memoryBucket)
+	for _, name := range buckets {
+		b := &memoryBucket{values: make(map[string][]byte)}
+		g[name] = b
+		t.copied[b] = true
+	}
+	t.state.generations[id] = g
+	t.copiedGenerations[id] = true
+	return &memoryGeneration{id: id, tx: t}, nil
+}
+
+func (t *memoryTx) DeleteGeneration(id string) error {
+	if !t.writable {
+		return errTxNotWritable
+	}
+	delete(t.state.generations, id)
+	delete(t.copiedGenerations, id)
+	return nil
+}
+
+func (t *memoryTx) NextSequence() (uint64, error) {
+	if !t.writable {
+		return 0, errTxNotWritable
+	}
+	t.state.sequence++
+	return t.state.sequence, nil
+}
+
+type memoryGeneration struct {
+	id string
+	tx *memoryTx
+}
+
+func (g *memoryGeneration) Bucket(name string) StoreBucket {
+	if g.tx.bucket(g.id, name) != nil {
+		return &memoryTxBucket{tx: g.tx, generation: g.id, name: name}
+	}
+	return nil
+}
+
+// memoryTxBucket is a bucket as seen by a transaction. It's looked up again on every access, as the
+// transaction replaces the buckets it copies.
+type memoryTxBucket struct {
+	tx         *memoryTx
+	generation string
+	name       string
+}
+
+func (b *memoryTxBucket) values() map[string][]byte {
+	if bucket := b.tx.bucket(b.generation, b.name); bucket != nil {
+		return bucket.values
+	}
+	return nil
+}
+
+func (b *memoryTxBucket) Get(key []byte) []byte {
+	return b.values()[string(key)]
+}
+
+func (b *memoryTxBucket) Put(key []byte, value []byte) error {
+	if !b.tx.writable {
+		return errTxNotWritable
+	}
+	b.tx.writableBucket(b.generation, b.name).values[string(key)] = append([]byte{}, value...)
+	return nil
+}
+
+func (b *memoryTxBucket) Delete(key []byte) error {
+	if !b.tx.writable {
+		return errTxNotWritable
+	}
+	delete(b.tx.writableBucket(b.generation, b.name).values, string(key))
+	return nil
+}
+
+func (b *memoryTxBucket) ForEach(fn func(k, v []byte) error) error {
+	values := b.values()
+	for _, key := range sortedKeys(values) {
+		if err := fn([]byte(key), values[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *memoryTxBucket) Cursor() StoreCursor {
+	return &memoryCursor{bucket: b, keys: sortedKeys(b.values())}
+}
+
+func (b *memoryTxBucket) Count() int {
+	return len(b.values())
+}
+
+func sortedKeys(values map[string][]byte) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// memoryCursor walks the keys a bucket had when the cursor was created, skipping the keys deleted since
+type memoryCursor struct {
+	bucket *memoryTxBucket
+	keys   []string
+	i      int
+}
+
+func (c *memoryCursor) First() ([]byte, []byte) {
+	c.i = -1
+	return c.Next()
+}
+
+func (c *memoryCursor) Last() ([]byte, []byte) {
+	c.i = len(c.keys)
+	return c.Prev()
+}
+
+func (c *memoryCursor) Next() ([]byte, []byte) {
+	return c.move(1)
+}
+
+func (c *memoryCursor) Prev() ([]byte, []byte) {
+	return c.move(-1)
+}
+
+func (c *memoryCursor) move(step int) ([]byte, []byte) {
+	for c.i += step; c.i >= 0 && c.i < len(c.keys); c.i += step {
+		if v, ok := c.bucket.values()[c.keys[c.i]]; ok {
+			return []byte(c.keys[c.i]), v
+		}
+	}
+	if c.i < 0 {
+		c.i = -1
+	} else {
+		c.i = len(c.keys)
+	}
+	return nil, nil
+}
@@ -0,0 +1,130 @@
+package authors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+// MergeStrategy - how a field is merged when an author has both TME and curated values for it
+type MergeStrategy string
+
+const (
+	// PreferCurated - the curated value replaces the TME value, even when it is empty
+	PreferCurated MergeStrategy = "prefer-curated"
+	// PreferTME - the TME value is kept, even when it is empty
+	PreferTME MergeStrategy = "prefer-tme"
+	// CuratedIfNonEmpty - the curated value replaces the TME value only when it has one
+	CuratedIfNonEmpty MergeStrategy = "curated-if-non-empty"
+	// Union - both values are kept, without duplicates. Only valid for lists.
+	Union MergeStrategy = "union"
+
+	sourceMerged = sourceTME + "+" + sourceCurated
+)
+
+// MergePolicy - the merge strategy for each author field, keyed by the field's JSON name
+type MergePolicy map[string]MergeStrategy
+
+// listFields are the author fields which hold several values
+var listFields = map[string]bool{"aliases": true}
+
+// DefaultMergePolicy - curated values always win, except for aliases which are combined with the TME ones
+func DefaultMergePolicy() MergePolicy {
+	p := MergePolicy{"aliases": Union}
+	for _, f := range curatedFields {
+		p[f] = PreferCurated
+	}
+	return p
+}
+
+// LoadMergePolicy - read a JSON merge policy from a file. Fields it doesn't mention keep their default strategy.
+func LoadMergePolicy(fileName string) (MergePolicy, error) {
+	p := DefaultMergePolicy()
+	if fileName == "" {
+		return p, nil
+	}
+	contents, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	var configured MergePolicy
+	if err := json.Unmarshal(contents, &configured); err != nil {
+		return nil, fmt.Errorf("Merge policy %v is not valid JSON: %v", fileName, err)
+	}
+	for field, strategy := range configured {
+		if err := validateStrategy(field, strategy); err != nil {
+			return nil, err
+		}
+		p[field] = strategy
+	}
+	return p, nil
+}
+
+func validateStrategy(field string, strategy MergeStrategy) error {
+	if _, known := DefaultMergePolicy()[field]; !known {
+		return fmt.Errorf("Merge policy field %v is not a curated author field", field)
+	}
+	switch strategy {
+	case PreferCurated, PreferTME, CuratedIfNonEmpty:
+		return nil
+	case Union:
+		if listFields[field] {
+			return nil
+		}
+		return fmt.Errorf("Merge strategy %v can only be used for lists, not %v", strategy, field)
+	}
+	return fmt.Errorf("Unknown merge strategy %v for %v", strategy, field)
+}
+
+func (p MergePolicy) strategy(field string) MergeStrategy {
+	if s, ok := p[field]; ok {
+		return s
+	}
+	return DefaultMergePolicy()[field]
+}
+
+// merge applies the curated values to a TME author and returns the source of each field it changed
+func (p MergePolicy) merge(a author, curated author) (author, map[string]string) {
+	sources := make(map[string]string)
+	scalars := map[string][2]*string{
+		"name":            {&a.Name, &curated.Name},
+		"prefLabel":       {&a.PrefLabel, &curated.PrefLabel},
+		"emailAddress":    {&a.EmailAddress, &curated.EmailAddress},
+		"twitterHandle":   {&a.TwitterHandle, &curated.TwitterHandle},
+		"facebookProfile": {&a.FacebookProfile, &curated.FacebookProfile},
+		"linkedinProfile": {&a.LinkedinProfile, &curated.LinkedinProfile},
+		"description":     {&a.Description, &curated.Description},
+		"descriptionXML":  {&a.DescriptionXML, &curated.DescriptionXML},
+		"_imageUrl":       {&a.ImageURL, &curated.ImageURL},
+	}
+	for field, values := range scalars {
+		if takeCurated(p.strategy(field), *values[1] == "") {
+			*values[0] = *values[1]
+			sources[field] = sourceCurated
+		}
+	}
+
+	switch strategy := p.strategy("aliases"); strategy {
+	case Union:
+		if len(curated.Aliases) > 0 {
+			a.Aliases = dedupeAliases(append(append([]string{}, a.Aliases...), curated.Aliases...))
+			sources["aliases"] = sourceMerged
+		}
+	default:
+		if takeCurated(strategy, len(curated.Aliases) == 0) {
+			a.Aliases = curated.Aliases
+			sources["aliases"] = sourceCurated
+		}
+	}
+	return a, sources
+}
+
+func takeCurated(strategy MergeStrategy, curatedEmpty bool) bool {
+	switch strategy {
+	case PreferCurated:
+		return true
+	case CuratedIfNonEmpty:
+		return !curatedEmpty
+	}
+	return false
+}
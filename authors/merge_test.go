@@ -0,0 +1,137 @@
+package authors
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergePolicy(t *testing.T) {
+	tmeAuthor := author{
+		UUID:          "e807f1fc-f82d-332f-9bb0-18ca6738a19f",
+		Name:          "Fred Black",
+		PrefLabel:     "Fred Black",
+		Type:          "Person",
+		TwitterHandle: "@fredtme",
+		Aliases:       []string{"F Black", "Fred Black"},
+	}
+	curatedAuthor := author{
+		UUID:         "e807f1fc-f82d-332f-9bb0-18ca6738a19f",
+		Name:         "Frederick Black",
+		PrefLabel:    "Frederick Black",
+		EmailAddress: "fred@black.com",
+		Aliases:      []string{"Frederick Black", "fred black"},
+	}
+
+	tests := []struct {
+		name            string
+		policy          MergePolicy
+		expectedName    string
+		expectedEmail   string
+		expectedTwitter string
+		expectedAliases []string
+		expectedSources map[string]string
+	}{
+		{"Default policy overwrites everything and combines the aliases",
+			DefaultMergePolicy(),
+			"Frederick Black", "fred@black.com", "", []string{"F Black", "Fred Black", "Frederick Black"},
+			map[string]string{"name": "Bertha", "prefLabel": "Bertha", "emailAddress": "Bertha", "twitterHandle": "Bertha", "facebookProfile": "Bertha",
+				"linkedinProfile": "Bertha", "description": "Bertha", "descriptionXML": "Bertha", "_imageUrl": "Bertha", "aliases": "TME+Bertha"}},
+		{"Nil policy behaves as the default",
+			nil,
+			"Frederick Black", "fred@black.com", "", []string{"F Black", "Fred Black", "Frederick Black"},
+			map[string]string{"name": "Bertha", "prefLabel": "Bertha", "emailAddress": "Bertha", "twitterHandle": "Bertha", "facebookProfile": "Bertha",
+				"linkedinProfile": "Bertha", "description": "Bertha", "descriptionXML": "Bertha", "_imageUrl": "Bertha", "aliases": "TME+Bertha"}},
+		{"Prefer TME keeps TME values",
+			policyOf(PreferTME),
+			"Fred Black", "", "@fredtme", []string{"F Black", "Fred Black"},
+			map[string]string{}},
+		{"Curated if non empty keeps TME values where the curated cell is empty",
+			policyOf(CuratedIfNonEmpty),
+			"Frederick Black", "fred@black.com", "@fredtme", []string{"Frederick Black", "fred black"},
+			map[string]string{"name": "Bertha", "prefLabel": "Bertha", "emailAddress": "Bertha", "aliases": "Bertha"}},
+		{"Union ignores aliases which only differ by case",
+			MergePolicy{"aliases": Union},
+			"Frederick Black", "fred@black.com", "", []string{"F Black", "Fred Black", "Frederick Black"},
+			map[string]string{"name": "Bertha", "prefLabel": "Bertha", "emailAddress": "Bertha", "twitterHandle": "Bertha", "facebookProfile": "Bertha",
+				"linkedinProfile": "Bertha", "description": "Bertha", "descriptionXML": "Bertha", "_imageUrl": "Bertha", "aliases": "TME+Bertha"}},
+	}
+
+	for _, test := range tests {
+		merged, sources := test.policy.merge(tmeAuthor, curatedAuthor)
+		assert.Equal(t, test.expectedName, merged.Name, test.name)
+		assert.Equal(t, test.expectedName, merged.PrefLabel, test.name)
+		assert.Equal(t, test.expectedEmail, merged.EmailAddress, test.name)
+		assert.Equal(t, test.expectedTwitter, merged.TwitterHandle, test.name)
+		assert.Equal(t, test.expectedAliases, merged.Aliases, test.name)
+		assert.Equal(t, test.expectedSources, sources, test.name)
+		assert.Equal(t, "Person", merged.Type, test.name)
+	}
+}
+
+func TestLoadMergePolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		expected MergePolicy
+		err      string
+	}{
+		{"Overrides some fields",
+			`{"name": "prefer-tme", "aliases": "prefer-tme"}`,
+			mergePolicyWith(MergePolicy{"name": PreferTME, "aliases": PreferTME}),
+			""},
+		{"Unknown field",
+			`{"birthYear": "prefer-tme"}`,
+			nil,
+			"Merge policy field birthYear is not a curated author field"},
+		{"Unknown strategy",
+			`{"name": "prefer-spreadsheet"}`,
+			nil,
+			"Unknown merge strategy prefer-spreadsheet for name"},
+		{"Union of a single value",
+			`{"emailAddress": "union"}`,
+			nil,
+			"Merge strategy union can only be used for lists, not emailAddress"},
+	}
+
+	for _, test := range tests {
+		tmpfile, err := ioutil.TempFile("", "merge-policy")
+		assert.NoError(t, err)
+		_, err = tmpfile.WriteString(test.config)
+		assert.NoError(t, err)
+		assert.NoError(t, tmpfile.Close())
+
+		policy, err := LoadMergePolicy(tmpfile.Name())
+		os.Remove(tmpfile.Name())
+		if test.err != "" {
+			assert.EqualError(t, err, test.err, test.name)
+			continue
+		}
+		assert.NoError(t, err, test.name)
+		assert.Equal(t, test.expected, policy, test.name)
+	}
+}
+
+func TestLoadMergePolicyWithoutFile(t *testing.T) {
+	policy, err := LoadMergePolicy("")
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultMergePolicy(), policy)
+}
+
+func policyOf(strategy MergeStrategy) MergePolicy {
+	p := MergePolicy{}
+	for field := range DefaultMergePolicy() {
+		p[field] = strategy
+	}
+	return p
+}
+
+func mergePolicyWith(overrides MergePolicy) MergePolicy {
+	p := DefaultMergePolicy()
+	for field, strategy := range overrides {
+		p[field] = strategy
+	}
+	return p
+}
@@ -1,30 +1,48 @@
 package authors
 
+import (
+	"encoding/json"
+	"time"
+)
+
 type berthaAuthor struct {
-	Name            string `json:"name"`
-	Email           string `json:"email"`
-	ImageURL        string `json:"imageurl"`
-	Biography       string `json:"biography"`
-	TwitterHandle   string `json:"twitterhandle"`
-	FacebookProfile string `json:"facebookprofile"`
-	LinkedinProfile string `json:"linkedinprofile"`
-	TmeIdentifier   string `json:"tmeidentifier"`
+	Name                    string `json:"name"`
+	Email                   string `json:"email"`
+	ImageURL                string `json:"imageurl"`
+	Biography               string `json:"biography"`
+	TwitterHandle           string `json:"twitterhandle"`
+	FacebookProfile         string `json:"facebookprofile"`
+	LinkedinProfile         string `json:"linkedinprofile"`
+	TmeIdentifier           string `json:"tmeidentifier"`
+	Aliases                 string `json:"aliases"`
+	SecondaryTmeIdentifiers string `json:"secondarytmeidentifiers"`
+	Mastodon                string `json:"mastodon"`
+	Bluesky                 string `json:"bluesky"`
+	Instagram               string `json:"instagram"`
+	Website                 string `json:"website"`
+	// Raw is the JSON of the row as Bertha sent it
+	Raw json.RawMessage `json:"-"`
 }
 
 type author struct {
 	UUID                   string                 `json:"uuid"`
 	PrefLabel              string                 `json:"prefLabel"`
 	Type                   string                 `json:"type"`
+	Taxonomies             []string               `json:"taxonomies,omitempty"`
 	AlternativeIdentifiers alternativeIdentifiers `json:"alternativeIdentifiers,omitempty"`
 	Aliases                []string               `json:"aliases,omitempty"`
 	BirthYear              int                    `json:"birthYear,omitempty"`
 	Name                   string                 `json:"name"`
 	Salutation             string                 `json:"salutation,omitempty"`
+	GivenName              string                 `json:"givenName,omitempty"`
+	FamilyName             string                 `json:"familyName,omitempty"`
 	EmailAddress           string                 `json:"emailAddress,omitempty"`
 	TwitterHandle          string                 `json:"twitterHandle,omitempty"`
 	FacebookProfile        string                 `json:"facebookProfile,omitempty"`
 	LinkedinProfile        string                 `json:"linkedinProfile,omitempty"`
+	SocialProfiles         []socialProfile        `json:"socialProfiles,omitempty"`
 	Description            string                 `json:"description,omitempty"`
+	ShortDescription       string                 `json:"shortDescription,omitempty"`
 	DescriptionXML         string                 `json:"descriptionXML,omitempty"`
 	ImageURL               string                 `json:"_imageUrl,omitempty"`
 }
@@ -41,3 +59,167 @@ type authorLink struct {
 type authorUUID struct {
 	UUID string `json:"ID"`
 }
+
+type unmatchedCuratedAuthor struct {
+	Name           string        `json:"name"`
+	TmeIdentifier  string        `json:"tmeIdentifier"`
+	UUID           string        `json:"uuid"`
+	ClosestMatches []authorMatch `json:"closestMatches,omitempty"`
+}
+
+type authorMatch struct {
+	UUID      string  `json:"uuid"`
+	PrefLabel string  `json:"prefLabel"`
+	Score     float64 `json:"score"`
+}
+
+type duplicateGroup struct {
+	Score     float64           `json:"score"`
+	MatchedOn []string          `json:"matchedOn"`
+	Authors   []duplicateAuthor `json:"authors"`
+}
+
+type duplicateAuthor struct {
+	UUID          string `json:"uuid"`
+	PrefLabel     string `json:"prefLabel"`
+	TmeIdentifier string `json:"tmeIdentifier,omitempty"`
+	EmailAddress  string `json:"emailAddress,omitempty"`
+}
+
+type socialProfile struct {
+	Network string `json:"network"`
+	Handle  string `json:"handle,omitempty"`
+	URL     string `json:"url"`
+}
+
+type reloadReport struct {
+	StartedAt      time.Time             `json:"startedAt"`
+	DryRun         bool                  `json:"dryRun,omitempty"`
+	FinishedAt     *time.Time            `json:"finishedAt,omitempty"`
+	Descriptions   []descriptionReport   `json:"descriptions"`
+	SocialProfiles []socialProfileReport `json:"socialProfiles"`
+	Images         []imageReport         `json:"images"`
+	FailedPages    []failedPage          `json:"failedPages"`
+	Compaction     *compaction           `json:"compaction,omitempty"`
+}
+
+type generation struct {
+	ID             string     `json:"id"`
+	Status         string     `json:"status"`
+	StartedAt      time.Time  `json:"startedAt"`
+	FinishedAt     *time.Time `json:"finishedAt,omitempty"`
+	ActivatedAt    *time.Time `json:"activatedAt,omitempty"`
+	Count          int        `json:"count"`
+	CuratedMatches int        `json:"curatedMatches"`
+	BlockedReasons []string   `json:"blockedReasons,omitempty"`
+	DryRun         bool       `json:"dryRun,omitempty"`
+	SnapshotOf     string     `json:"snapshotOf,omitempty"`
+	// results are what loading its curated authors found, kept in memory only
+	results *curatedResults
+}
+
+type authorDiff struct {
+	UUID      string        `json:"uuid"`
+	PrefLabel string        `json:"prefLabel"`
+	Change    string        `json:"change"`
+	Fields    []fieldChange `json:"fields"`
+}
+
+type fieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from,omitempty"`
+	To    interface{} `json:"to,omitempty"`
+}
+
+type diffSummary struct {
+	From    string              `json:"from"`
+	To      string              `json:"to"`
+	Added   []string            `json:"added"`
+	Removed []string            `json:"removed"`
+	Changed map[string][]string `json:"changed"`
+}
+
+// compaction is how much a compaction of the cache file after a reload shrank it
+type compaction struct {
+	StartedAt  time.Time `json:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt"`
+	SizeBefore int64     `json:"sizeBefore"`
+	SizeAfter  int64     `json:"sizeAfter"`
+	Error      string    `json:"error,omitempty"`
+}
+
+type failedPage struct {
+	Taxonomy string `json:"taxonomy"`
+	Offset   int    `json:"offset"`
+	Error    string `json:"error"`
+}
+
+type descriptionReport struct {
+	UUID          string   `json:"uuid"`
+	TmeIdentifier string   `json:"tmeIdentifier"`
+	Name          string   `json:"name"`
+	Changes       []string `json:"changes,omitempty"`
+	Error         string   `json:"error,omitempty"`
+}
+
+type socialProfileReport struct {
+	UUID          string `json:"uuid"`
+	TmeIdentifier string `json:"tmeIdentifier"`
+	Name          string `json:"name"`
+	Network       string `json:"network"`
+	Value         string `json:"value"`
+	Error         string `json:"error"`
+}
+
+type uuidMismatch struct {
+	UUID          string `json:"uuid"`
+	PrefLabel     string `json:"prefLabel"`
+	TmeIdentifier string `json:"tmeIdentifier,omitempty"`
+	ExpectedUUID  string `json:"expectedUuid,omitempty"`
+}
+
+type imageReport struct {
+	UUID          string `json:"uuid"`
+	TmeIdentifier string `json:"tmeIdentifier"`
+	Name          string `json:"name"`
+	ImageURL      string `json:"imageUrl"`
+	Error         string `json:"error"`
+}
+
+type authorSource struct {
+	UUID   string          `json:"uuid"`
+	TME    []tmeSource     `json:"tme,omitempty"`
+	Bertha json.RawMessage `json:"bertha,omitempty"`
+}
+
+type tmeSource struct {
+	TmeIdentifier string `json:"tmeIdentifier"`
+	Taxonomy      string `json:"taxonomy"`
+	XML           string `json:"xml"`
+}
+
+type authorProvenance struct {
+	UUID   string                     `json:"uuid"`
+	Fields map[string]fieldProvenance `json:"fields"`
+}
+
+type fieldProvenance struct {
+	Source    string    `json:"source"`
+	SourceID  string    `json:"sourceId"`
+	SourceRow int       `json:"sourceRow,omitempty"`
+	LoadedAt  time.Time `json:"loadedAt"`
+}
+
+type authorOverride struct {
+	UUID      string            `json:"uuid"`
+	Fields    map[string]string `json:"fields"`
+	User      string            `json:"user"`
+	Reason    string            `json:"reason"`
+	UpdatedAt time.Time         `json:"updatedAt"`
+}
+
+type overrideRequest struct {
+	Fields map[string]*string `json:"fields"`
+	User   string             `json:"user"`
+	Reason string             `json:"reason"`
+}
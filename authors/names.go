@@ -0,0 +1,147 @@
+package authors
+
+import "strings"
+
+// structuredNameFields are the author fields derived from the name
+var structuredNameFields = []string{"salutation", "givenName", "familyName"}
+
+type personName struct {
+	Salutation string
+	GivenName  string
+	FamilyName string
+	Suffix     string
+}
+
+// honorifics are the titles which can precede a name, in lower case without a trailing full stop
+var honorifics = map[string]bool{
+	"mr": true, "mrs": true, "ms": true, "miss": true, "mx": true,
+	"dr": true, "prof": true, "professor": true,
+	"sir": true, "dame": true, "lord": true, "lady": true, "baron": true, "baroness": true,
+	"rev": true, "revd": true, "reverend": true, "fr": true, "father": true, "rabbi": true, "imam": true, "sheikh": true,
+	"rt": true, "hon": true,
+}
+
+// suffixes are generational suffixes, honours and post-nominals which can follow a name
+var suffixes = map[string]bool{
+	"jr": true, "jnr": true, "sr": true, "snr": true, "ii": true, "iii": true, "iv": true,
+	"qc": true, "kc": true, "mp": true, "mep": true, "phd": true, "frs": true,
+	"cbe": true, "obe": true, "mbe": true, "kbe": true, "dbe": true, "cmg": true,
+}
+
+// particles start a family name when they follow the given name, as in "Ludwig von Mises" or "Ahmed Al Omran"
+var particles = map[string]bool{
+	"van": true, "von": true, "der": true, "den": true, "de": true, "del": true, "della": true, "di": true, "da": true,
+	"du": true, "le": true, "la": true, "dos": true, "das": true, "ter": true, "ten": true,
+	"bin": true, "ibn": true, "al": true, "el": true, "abu": true,
+}
+
+// particlePrefixes start a family name when a token begins with them, as in "Ahmed al-Omran"
+var particlePrefixes = []string{"al-", "el-"}
+
+// parseName splits a full name into its salutation, given and family names and suffix.
+// "Family, Given" names are supported. When a name has a single word it is only taken
+// as a family name if a salutation precedes it, as in "Dr Who".
+func parseName(fullName string) personName {
+	var n personName
+	name := fullName
+	var reversedGiven []string
+	if i := strings.Index(name, ","); i >= 0 {
+		afterComma := strings.Fields(name[i+1:])
+		if !allSuffixes(afterComma) {
+			reversedGiven = afterComma
+			name = name[:i]
+		}
+	}
+
+	tokens := strings.Fields(strings.Replace(name, ",", " ", -1))
+	if reversedGiven != nil {
+		tokens = append(reversedGiven, tokens...)
+	}
+
+	var salutation []string
+	for len(tokens) > 1 && isHonorific(tokens) {
+		salutation = append(salutation, tokens[0])
+		tokens = tokens[1:]
+	}
+	n.Salutation = strings.Join(salutation, " ")
+
+	var suffix []string
+	for len(tokens) > 1 && suffixes[token(tokens[len(tokens)-1])] {
+		suffix = append([]string{tokens[len(tokens)-1]}, suffix...)
+		tokens = tokens[:len(tokens)-1]
+	}
+	n.Suffix = strings.Join(suffix, " ")
+
+	switch {
+	case len(tokens) == 0:
+		return n
+	case len(tokens) == 1:
+		if n.Salutation != "" {
+			n.FamilyName = tokens[0]
+		}
+		return n
+	case reversedGiven != nil && len(reversedGiven) > len(salutation):
+		givenCount := len(reversedGiven) - len(salutation)
+		n.GivenName = strings.Join(tokens[:givenCount], " ")
+		n.FamilyName = strings.Join(tokens[givenCount:], " ")
+		return n
+	}
+
+	familyStart := len(tokens) - 1
+	for i := 1; i < len(tokens)-1; i++ {
+		if isParticle(tokens[i]) {
+			familyStart = i
+			break
+		}
+	}
+	n.GivenName = strings.Join(tokens[:familyStart], " ")
+	n.FamilyName = strings.Join(tokens[familyStart:], " ")
+	return n
+}
+
+func token(t string) string {
+	return strings.TrimSuffix(strings.ToLower(t), ".")
+}
+
+// isHonorific tells whether a name starts with an honorific. "The" is only one before another honorific, as in
+// "The Rt Hon", so that "The Editorial Board" has no salutation.
+func isHonorific(tokens []string) bool {
+	if token(tokens[0]) == "the" {
+		return len(tokens) > 2 && honorifics[token(tokens[1])]
+	}
+	return honorifics[token(tokens[0])]
+}
+
+func allSuffixes(tokens []string) bool {
+	if len(tokens) == 0 {
+		return false
+	}
+	for _, t := range tokens {
+		if !suffixes[token(t)] {
+			return false
+		}
+	}
+	return true
+}
+
+func isParticle(t string) bool {
+	lower := strings.ToLower(t)
+	if particles[lower] {
+		return true
+	}
+	for _, prefix := range particlePrefixes {
+		if strings.HasPrefix(lower, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// withParsedName fills the structured name fields of an author from its name
+func withParsedName(a author) author {
+	n := parseName(a.Name)
+	a.Salutation = n.Salutation
+	a.GivenName = n.GivenName
+	a.FamilyName = n.FamilyName
+	return a
+}
@@ -0,0 +1,65 @@
+package authors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseName(t *testing.T) {
+	tests := []struct {
+		byline   string
+		expected personName
+	}{
+		{"Martin Wolf", personName{GivenName: "Martin", FamilyName: "Wolf"}},
+		{"Gillian Tett", personName{GivenName: "Gillian", FamilyName: "Tett"}},
+		{"  Lionel   Barber ", personName{GivenName: "Lionel", FamilyName: "Barber"}},
+		{"John Paul Rathbone", personName{GivenName: "John Paul", FamilyName: "Rathbone"}},
+		{"Anne-Sylvaine Chassany", personName{GivenName: "Anne-Sylvaine", FamilyName: "Chassany"}},
+		{"Dr Jane Smith-Jones", personName{Salutation: "Dr", GivenName: "Jane", FamilyName: "Smith-Jones"}},
+		{"Mohamed El-Erian", personName{GivenName: "Mohamed", FamilyName: "El-Erian"}},
+		{"Mohamed A El-Erian", personName{GivenName: "Mohamed A", FamilyName: "El-Erian"}},
+		{"Ahmed Al Omran", personName{GivenName: "Ahmed", FamilyName: "Al Omran"}},
+		{"Ahmed al-Omran", personName{GivenName: "Ahmed", FamilyName: "al-Omran"}},
+		{"Jan van der Made", personName{GivenName: "Jan", FamilyName: "van der Made"}},
+		{"Ursula von der Leyen", personName{GivenName: "Ursula", FamilyName: "von der Leyen"}},
+		{"Mario Draghi", personName{GivenName: "Mario", FamilyName: "Draghi"}},
+		{"Luiz Inácio Lula da Silva", personName{GivenName: "Luiz Inácio Lula", FamilyName: "da Silva"}},
+		{"Prof Sir John Kay", personName{Salutation: "Prof Sir", GivenName: "John", FamilyName: "Kay"}},
+		{"Sir Howard Davies", personName{Salutation: "Sir", GivenName: "Howard", FamilyName: "Davies"}},
+		{"Lord Skidelsky", personName{Salutation: "Lord", FamilyName: "Skidelsky"}},
+		{"The Rt Hon. Dame Margaret Hodge MP", personName{Salutation: "The Rt Hon. Dame", GivenName: "Margaret", FamilyName: "Hodge", Suffix: "MP"}},
+		{"The Editorial Board", personName{GivenName: "The Editorial", FamilyName: "Board"}},
+		{"The Economist", personName{GivenName: "The", FamilyName: "Economist"}},
+		{"The Lord Skidelsky", personName{Salutation: "The Lord", FamilyName: "Skidelsky"}},
+		{"Mrs. Lucy Kellaway", personName{Salutation: "Mrs.", GivenName: "Lucy", FamilyName: "Kellaway"}},
+		{"Henry Mance Jr.", personName{GivenName: "Henry", FamilyName: "Mance", Suffix: "Jr."}},
+		{"Martin Wolf, CBE", personName{GivenName: "Martin", FamilyName: "Wolf", Suffix: "CBE"}},
+		{"Wolf, Martin", personName{GivenName: "Martin", FamilyName: "Wolf"}},
+		{"Lex", personName{}},
+		{"", personName{}},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, parseName(test.byline), test.byline)
+	}
+}
+
+func TestTransformAuthorParsesName(t *testing.T) {
+	tfp := transformAuthor(term{CanonicalName: "Dr Jane Smith-Jones", RawID: "jane"}, taxonomyName, AliasOptions{}, UUIDStrategy{})
+	assert.Equal(t, "Dr Jane Smith-Jones", tfp.Name)
+	assert.Equal(t, "Dr", tfp.Salutation)
+	assert.Equal(t, "Jane", tfp.GivenName)
+	assert.Equal(t, "Smith-Jones", tfp.FamilyName)
+}
+
+func TestAddBerthaInformationParsesCuratedName(t *testing.T) {
+	tmeAuthor := transformAuthor(term{CanonicalName: "Fred Black", RawID: "fred"}, taxonomyName, AliasOptions{}, UUIDStrategy{})
+	curated := berthaAuthor{Name: "Dr Frederick Black", TmeIdentifier: tmeAuthor.AlternativeIdentifiers.TME[0]}
+
+	merged, sources, err := addBerthaInformation(tmeAuthor, curated, DefaultMergePolicy(), UUIDStrategy{})
+	assert.NoError(t, err)
+	assert.Equal(t, "Dr", merged.Salutation)
+	assert.Equal(t, "Frederick", merged.GivenName)
+	assert.Equal(t, "Black", merged.FamilyName)
+	assert.Equal(t, "Bertha", sources["givenName"])
+}
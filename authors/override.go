@@ -0,0 +1,245 @@
+package authors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+const sourceOverride = "Override"
+
+// overridableFields are the author fields an editor can override, by JSON name
+var overridableFields = map[string]func(a *author) *string{
+	"name":            func(a *author) *string { return &a.Name },
+	"prefLabel":       func(a *author) *string { return &a.PrefLabel },
+	"emailAddress":    func(a *author) *string { return &a.EmailAddress },
+	"twitterHandle":   func(a *author) *string { return &a.TwitterHandle },
+	"facebookProfile": func(a *author) *string { return &a.FacebookProfile },
+	"linkedinProfile": func(a *author) *string { return &a.LinkedinProfile },
+	"description":     func(a *author) *string { return &a.Description },
+	"descriptionXML":  func(a *author) *string { return &a.DescriptionXML },
+	"_imageUrl":       func(a *author) *string { return &a.ImageURL },
+}
+
+// dependentFields are the fields derived from an overridable field, which are restored along with it
+var dependentFields = map[string][]string{
+	"name":            structuredNameFields,
+	"description":     {"shortDescription"},
+	"descriptionXML":  {"description", "shortDescription"},
+	"twitterHandle":   {"socialProfiles"},
+	"facebookProfile": {"socialProfiles"},
+	"linkedinProfile": {"socialProfiles"},
+}
+
+// profileFields are the overridable fields which hold a social profile, with its network
+var profileFields = map[string]string{"twitterHandle": networkTwitter, "facebookProfile": networkFacebook, "linkedinProfile": networkLinkedin}
+
+func validateOverrideRequest(req overrideRequest) error {
+	if req.User == "" {
+		return errors.New("user is mandatory")
+	}
+	if req.Reason == "" {
+		return errors.New("reason is mandatory")
+	}
+	if len(req.Fields) == 0 {
+		return errors.New("fields are mandatory")
+	}
+	for field, value := range req.Fields {
+		if _, ok := overridableFields[field]; !ok {
+			return fmt.Errorf("%v cannot be overridden", field)
+		}
+		if network, ok := profileFields[field]; ok && value != nil && *value != "" {
+			if _, err := normaliseSocialProfile(network, *value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// normaliseOverrideRequest sanitises the description XML and checks and rewrites the image URL a request overrides,
+// as it's done for the curated authors. It fails when either can't be used.
+func normaliseOverrideRequest(req overrideRequest, images ImageOptions) (overrideRequest, error) {
+	fields := make(map[string]*string, len(req.Fields))
+	for field, value := range req.Fields {
+		fields[field] = value
+	}
+	if xml := fields["descriptionXML"]; xml != nil {
+		sanitised, _, err := sanitiseDescription(*xml)
+		if err != nil {
+			return req, fmt.Errorf("descriptionXML is not valid: %v", err)
+		}
+		fields["descriptionXML"] = &sanitised
+	}
+	if imageURL := fields["_imageUrl"]; imageURL != nil {
+		normalised, err := normaliseImageURL(*imageURL, images)
+		if err != nil {
+			return req, fmt.Errorf("_imageUrl is not valid: %v", err)
+		}
+		fields["_imageUrl"] = &normalised
+	}
+	req.Fields = fields
+	return req, nil
+}
+
+// updateOverride applies an override request to the existing override of an author, if any.
+// With replace the fields of the request replace all existing fields, otherwise they are merged
+// and a null field removes that field from the override.
+func updateOverride(existing authorOverride, uuid string, req overrideRequest, replace bool, now time.Time) authorOverride {
+	o := authorOverride{UUID: uuid, Fields: make(map[string]string), User: req.User, Reason: req.Reason, UpdatedAt: now}
+	if !replace {
+		for field, value := range existing.Fields {
+			o.Fields[field] = value
+		}
+	}
+	for field, value := range req.Fields {
+		if value == nil {
+			delete(o.Fields, field)
+			continue
+		}
+		o.Fields[field] = *value
+	}
+	return o
+}
+
+// removedFields are the fields of an override which another override no longer sets
+func removedFields(existing authorOverride, o authorOverride) []string {
+	var removed []string
+	for field := range existing.Fields {
+		if _, ok := o.Fields[field]; !ok {
+			removed = append(removed, field)
+		}
+	}
+	sort.Strings(removed)
+	return removed
+}
+
+// restoreFields sets the fields of an author which are no longer overridden, and the fields derived from them, back
+// to their values in the author derived from its sources, along with their provenance
+func restoreFields(a author, p authorProvenance, derived author, derivedProvenance authorProvenance, removed []string) (author, authorProvenance, error) {
+	var fields []string
+	for _, field := range removed {
+		fields = append(append(fields, field), dependentFields[field]...)
+	}
+	current, err := jsonFields(a)
+	if err != nil {
+		return a, p, err
+	}
+	original, err := jsonFields(derived)
+	if err != nil {
+		return a, p, err
+	}
+	if p.Fields == nil {
+		p.Fields = make(map[string]fieldProvenance)
+	}
+	for _, field := range fields {
+		if value, ok := original[field]; ok {
+			current[field] = value
+		} else {
+			delete(current, field)
+		}
+		if fp, ok := derivedProvenance.Fields[field]; ok {
+			p.Fields[field] = fp
+		} else {
+			delete(p.Fields, field)
+		}
+	}
+	marshalledAuthor, err := json.Marshal(current)
+	if err != nil {
+		return a, p, err
+	}
+	var restored author
+	err = json.Unmarshal(marshalledAuthor, &restored)
+	return restored, p, err
+}
+
+// jsonFields are the fields of an author by JSON name, leaving out the empty ones
+func jsonFields(a author) (map[string]json.RawMessage, error) {
+	marshalledAuthor, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]json.RawMessage)
+	err = json.Unmarshal(marshalledAuthor, &fields)
+	return fields, err
+}
+
+// curatedRow is the row of the Bertha payload the curated fields of an author were loaded from, or 0 when none
+// of its fields comes from there
+func curatedRow(p authorProvenance) int {
+	for _, fp := range p.Fields {
+		if fp.SourceRow > 0 {
+			return fp.SourceRow
+		}
+	}
+	return 0
+}
+
+// applyOverride sets the overridden fields of an author. When only the description XML is
+// overridden the plain text description is derived from it, the short description always
+// follows the description and overridden social profiles are normalised.
+func applyOverride(a author, o authorOverride) (author, error) {
+	for field, value := range o.Fields {
+		*overridableFields[field](&a) = value
+	}
+	if _, ok := o.Fields["name"]; ok {
+		a = withParsedName(a)
+	}
+	if xml, ok := o.Fields["descriptionXML"]; ok {
+		if _, ok := o.Fields["description"]; !ok {
+			plainDescription, err := plainText(xml)
+			if err != nil {
+				return a, err
+			}
+			a.Description = plainDescription
+		}
+	}
+	if overridesDescription(o) {
+		a.ShortDescription = shortDescription(a.Description)
+	}
+	if overridesSocialProfile(o) {
+		a = withSocialProfiles(a, a.SocialProfiles)
+	}
+	return a, nil
+}
+
+func (p *authorProvenance) addOverrideProvenance(o authorOverride) {
+	if p.Fields == nil {
+		p.Fields = make(map[string]fieldProvenance)
+	}
+	fp := fieldProvenance{Source: sourceOverride, SourceID: o.User, LoadedAt: o.UpdatedAt}
+	for field := range o.Fields {
+		p.Fields[field] = fp
+	}
+	if _, ok := o.Fields["descriptionXML"]; ok {
+		p.Fields["description"] = fp
+	}
+	if overridesDescription(o) {
+		p.Fields["shortDescription"] = fp
+	}
+	if overridesSocialProfile(o) {
+		p.Fields["socialProfiles"] = fp
+	}
+	if _, ok := o.Fields["name"]; ok {
+		for _, f := range structuredNameFields {
+			p.Fields[f] = fp
+		}
+	}
+}
+
+func overridesDescription(o authorOverride) bool {
+	_, description := o.Fields["description"]
+	_, descriptionXML := o.Fields["descriptionXML"]
+	return description || descriptionXML
+}
+
+func overridesSocialProfile(o authorOverride) bool {
+	for field := range profileFields {
+		if _, ok := o.Fields[field]; ok {
+			return true
+		}
+	}
+	return false
+}
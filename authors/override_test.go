@@ -0,0 +1,46 @@
+package authors
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateOverride(t *testing.T) {
+	now := time.Date(2017, 12, 1, 10, 0, 0, 0, time.UTC)
+	name := "Frederick Black"
+	existing := authorOverride{UUID: "uuid", Fields: map[string]string{"emailAddress": "fred@black.com", "_imageUrl": "fred.jpg"}, User: "jane.doe", Reason: "New email"}
+	req := overrideRequest{Fields: map[string]*string{"name": &name, "_imageUrl": nil}, User: "john.doe", Reason: "Full name"}
+
+	patched := updateOverride(existing, "uuid", req, false, now)
+	assert.Equal(t, authorOverride{UUID: "uuid", Fields: map[string]string{"emailAddress": "fred@black.com", "name": name}, User: "john.doe", Reason: "Full name", UpdatedAt: now}, patched)
+
+	replaced := updateOverride(existing, "uuid", req, true, now)
+	assert.Equal(t, map[string]string{"name": name}, replaced.Fields)
+}
+
+func TestApplyOverride(t *testing.T) {
+	a := author{UUID: "uuid", Name: "Fred Black", PrefLabel: "Fred Black", Description: "Old", DescriptionXML: "<p>Old</p>"}
+	o := authorOverride{UUID: "uuid", Fields: map[string]string{"prefLabel": "Frederick Black", "descriptionXML": "<p>New</p>"}}
+
+	overridden, err := applyOverride(a, o)
+	assert.NoError(t, err)
+	assert.Equal(t, author{UUID: "uuid", Name: "Fred Black", PrefLabel: "Frederick Black", Description: "New", ShortDescription: "New", DescriptionXML: "<p>New</p>"}, overridden)
+}
+
+func TestNormaliseOverrideRequest(t *testing.T) {
+	bio := `<p>New<script>steal()</script></p>`
+	image := "fthead:fred-black"
+	req := overrideRequest{Fields: map[string]*string{"descriptionXML": &bio, "_imageUrl": &image, "name": nil}, User: "jane.doe", Reason: "New photo"}
+
+	normalised, err := normaliseOverrideRequest(req, ImageOptions{Origami: true, Width: 150})
+	assert.NoError(t, err)
+	assert.Equal(t, "<p>New</p>", *normalised.Fields["descriptionXML"])
+	assert.Equal(t, "https://www.ft.com/__origami/service/image/v2/images/raw/fthead:fred-black?source=next&width=150", *normalised.Fields["_imageUrl"])
+	assert.Nil(t, normalised.Fields["name"])
+	assert.Equal(t, `<p>New<script>steal()</script></p>`, *req.Fields["descriptionXML"], "The request should be left as it was")
+
+	_, err = normaliseOverrideRequest(req, ImageOptions{})
+	assert.EqualError(t, err, "_imageUrl is not valid: fthead:fred-black is an Origami image reference but Origami rewriting is off")
+}
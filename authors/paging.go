@@ -0,0 +1,112 @@
+package authors
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// PagingOptions - how failures to fetch pages of TME terms are handled
+type PagingOptions struct {
+	// Retries is how many times a page is fetched again before it fails
+	Retries int
+	// Backoff is the wait before the first retry of a page, doubled before each next one
+	Backoff time.Duration
+	// MaxFailedPages is how many pages can fail before the whole load fails
+	MaxFailedPages int
+}
+
+// fetchPage fetches the page of TME terms of a taxonomy at an offset, retrying with backoff
+func (s *authorServiceImpl) fetchPage(taxonomy Taxonomy, offset int) ([]interface{}, error) {
+	backoff := s.paging.Backoff
+	terms, err := taxonomy.Repository.GetTmeTermsFromIndex(offset)
+	for retry := 1; err != nil && retry <= s.paging.Retries; retry++ {
+		log.Warnf("Error fetching TME page at offset %d of %v, retrying in %v: %v", offset, taxonomy.Name, backoff, err)
+		time.Sleep(backoff)
+		backoff *= 2
+		terms, err = taxonomy.Repository.GetTmeTermsFromIndex(offset)
+	}
+	return terms, err
+}
+
+// addFailedPage reports a page which failed after its retries. It fails when more pages than tolerated have failed.
+func (s *authorServiceImpl) addFailedPage(taxonomyName string, offset int, err error) error {
+	s.Lock()
+	defer s.Unlock()
+	log.Errorf("Failed to fetch TME page at offset %d of %v: %v", offset, taxonomyName, err)
+	s.report.FailedPages = append(s.report.FailedPages, failedPage{Taxonomy: taxonomyName, Offset: offset, Error: err.Error()})
+	if len(s.report.FailedPages) > s.paging.MaxFailedPages {
+		return fmt.Errorf("%d TME pages failed, more than the %d tolerated: %v", len(s.report.FailedPages), s.paging.MaxFailedPages, err)
+	}
+	return nil
+}
+
+func (s *authorServiceImpl) taxonomy(name string) (Taxonomy, bool) {
+	for _, t := range s.taxonomies {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Taxonomy{}, false
+}
+
+// retryFailedPages fetches the pages which failed during the last reload again, stores their authors into the
+// generation of the reload and applies the curated authors, overrides and concordance to the authors they add.
+// The generation is activated, unless it already is or the reload guard blocks it. The pages which fail again
+// stay in the reload report.
+func (s *authorServiceImpl) retryFailedPages() error {
+	s.reloading.Lock()
+	defer s.reloading.Unlock()
+	s.RLock()
+	failed := s.report.FailedPages
+	building := s.building
+	s.RUnlock()
+	if len(failed) == 0 {
+		return nil
+	}
+	if building == "" {
+		return errors.New("The last reload was abandoned, its failed pages can't be retried")
+	}
+
+	identities, err := s.readIdentities(building)
+	if err != nil {
+		return err
+	}
+	added := make(map[string]bool)
+	var wg sync.WaitGroup
+	c := make(chan []transformedTerm)
+	go s.processAuthors(c, &wg, building, time.Now(), identities, added)
+	stillFailed := []failedPage{}
+	for _, page := range failed {
+		taxonomy, ok := s.taxonomy(page.Taxonomy)
+		if !ok {
+			continue
+		}
+		terms, err := s.fetchPage(taxonomy, page.Offset)
+		if err != nil {
+			log.Errorf("Failed again to fetch TME page at offset %d of %v: %v", page.Offset, page.Taxonomy, err)
+			stillFailed = append(stillFailed, failedPage{Taxonomy: page.Taxonomy, Offset: page.Offset, Error: err.Error()})
+			continue
+		}
+		if len(terms) > 0 {
+			wg.Add(1)
+			s.processTerms(terms, taxonomy.Name, c)
+		}
+	}
+	close(c)
+	wg.Wait()
+
+	s.Lock()
+	s.report.FailedPages = stillFailed
+	s.Unlock()
+	log.Infof("Retried %d failed TME pages, %d still failing.", len(failed), len(stillFailed))
+	err = s.applyCuratedData(added)
+	if _, finishErr := s.finishGeneration(); finishErr != nil {
+		return finishErr
+	}
+	s.serveActiveGeneration()
+	return err
+}
@@ -0,0 +1,130 @@
+package authors
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// flakyRepo returns a term per page and fails the pages at some offsets a number of times
+type flakyRepo struct {
+	sync.Mutex
+	terms    []term
+	failures map[int]int
+}
+
+func (r *flakyRepo) GetTmeTermsFromIndex(startRecord int) ([]interface{}, error) {
+	r.Lock()
+	defer r.Unlock()
+	if r.failures[startRecord] > 0 {
+		r.failures[startRecord]--
+		return nil, errors.New("TME timed out")
+	}
+	if startRecord >= len(r.terms) {
+		return []interface{}{}, nil
+	}
+	return []interface{}{r.terms[startRecord]}, nil
+}
+
+func (r *flakyRepo) GetTmeTermById(rawID string) (interface{}, error) {
+	return nil, nil
+}
+
+func (r *flakyRepo) recover() {
+	r.Lock()
+	defer r.Unlock()
+	r.failures = map[int]int{}
+}
+
+func newFlakyRepo(failures map[int]int) *flakyRepo {
+	return &flakyRepo{terms: []term{
+		{CanonicalName: "Fred Black", RawID: "fred"},
+		{CanonicalName: "Terry Orange", RawID: "terry"},
+		{CanonicalName: "Lucy Kellaway", RawID: "kellaway"},
+	}, failures: failures}
+}
+
+func createPagingTestService(repo *flakyRepo, cacheFileName string, paging PagingOptions) AuthorService {
+	return createAuthorService([]Taxonomy{{Name: "taxonomy_string", Repository: repo}}, NewBoltAuthorStore(cacheFileName), &mockClient{resp: []berthaAuthor{}}, ServiceOptions{Paging: paging})
+}
+
+func TestFailedPageIsRetried(t *testing.T) {
+	tmpfile := getTempFile(t)
+	defer os.Remove(tmpfile.Name())
+	service := createPagingTestService(newFlakyRepo(map[int]int{1: 2}), tmpfile.Name(), PagingOptions{Retries: 2, Backoff: time.Millisecond})
+	defer service.Shutdown()
+	waitTillInit(t, service)
+	waitTillReloadFinished(t, service)
+	assertCount(t, service, 3)
+	assert.Empty(t, service.getReloadReport().FailedPages)
+}
+
+func TestTooManyFailedPagesFailTheLoad(t *testing.T) {
+	tmpfile := getTempFile(t)
+	defer os.Remove(tmpfile.Name())
+	service := createPagingTestService(newFlakyRepo(map[int]int{1: 3}), tmpfile.Name(), PagingOptions{Retries: 1, Backoff: time.Millisecond})
+	defer service.Shutdown()
+	waitTillInit(t, service)
+	waitTillReloadFinished(t, service)
+	assert.False(t, service.isDataLoaded())
+	assertCount(t, service, 0)
+	assert.Equal(t, []failedPage{{Taxonomy: "taxonomy_string", Offset: 1, Error: "TME timed out"}}, service.getReloadReport().FailedPages)
+	assert.Error(t, service.retryFailedPages())
+}
+
+func TestFailedPagesAreToleratedAndRetried(t *testing.T) {
+	tmpfile := getTempFile(t)
+	defer os.Remove(tmpfile.Name())
+	repo := newFlakyRepo(map[int]int{1: 100})
+	service := createPagingTestService(repo, tmpfile.Name(), PagingOptions{Retries: 1, Backoff: time.Millisecond, MaxFailedPages: 1})
+	defer service.Shutdown()
+	waitTillInit(t, service)
+	waitTillReloadFinished(t, service)
+	waitTillDataLoaded(t, service)
+	assertCount(t, service, 2)
+	assert.Equal(t, []failedPage{{Taxonomy: "taxonomy_string", Offset: 1, Error: "TME timed out"}}, service.getReloadReport().FailedPages)
+
+	assert.NoError(t, service.retryFailedPages())
+	assertCount(t, service, 2)
+	assert.Len(t, service.getReloadReport().FailedPages, 1)
+
+	repo.recover()
+	assert.NoError(t, service.retryFailedPages())
+	assertCount(t, service, 3)
+	assert.Empty(t, service.getReloadReport().FailedPages)
+	_, found, err := service.getAuthorByUUID(UUIDStrategy{}.uuidOf(buildTmeIdentifier("terry", "taxonomy_string")))
+	assert.NoError(t, err)
+	assert.True(t, found)
+}
+
+func TestRetriedPagesAreMergedWithTheAuthorsOfOtherTaxonomies(t *testing.T) {
+	tmpfile := getTempFile(t)
+	defer os.Remove(tmpfile.Name())
+	authorsRepo := dummyRepo{terms: []term{{CanonicalName: "Martin Wolf", RawID: "wolf"}}}
+	columnistsRepo := &flakyRepo{terms: []term{{CanonicalName: "Martin Wolf", RawID: "wolf"}}, failures: map[int]int{0: 100}}
+	service := createAuthorService([]Taxonomy{{Name: "Authors", Repository: &authorsRepo}, {Name: "Columnists", Repository: columnistsRepo}}, NewBoltAuthorStore(tmpfile.Name()), &mockClient{resp: []berthaAuthor{}}, ServiceOptions{Paging: PagingOptions{MaxFailedPages: 1}})
+	defer service.Shutdown()
+	waitTillInit(t, service)
+	waitTillReloadFinished(t, service)
+	waitTillDataLoaded(t, service)
+	assertCount(t, service, 1)
+
+	columnistsRepo.recover()
+	assert.NoError(t, service.retryFailedPages())
+	assertCount(t, service, 1)
+	wolf, found, err := service.getAuthorByUUID(UUIDStrategy{}.uuidOf(buildTmeIdentifier("wolf", "Authors")))
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []string{"Authors", "Columnists"}, wolf.Taxonomies)
+
+	generations, err := service.getGenerations()
+	assert.NoError(t, err)
+	if assert.Len(t, generations, 1) {
+		assert.Equal(t, "active", generations[0].Status)
+		assert.Equal(t, 1, generations[0].Count)
+	}
+}
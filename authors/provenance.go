@@ -0,0 +1,49 @@
+package authors
+
+import "time"
+
+const (
+	sourceTME     = "TME"
+	sourceCurated = "Bertha"
+)
+
+// tmeFields are the author fields populated from a TME term
+var tmeFields = append([]string{"uuid", "prefLabel", "type", "alternativeIdentifiers", "aliases", "name", "taxonomies"}, structuredNameFields...)
+
+// curatedFields are the author fields populated from a curated Bertha row
+var curatedFields = []string{"name", "prefLabel", "emailAddress", "twitterHandle", "facebookProfile", "linkedinProfile", "description", "descriptionXML", "_imageUrl"}
+
+// curatedOnlyFields are the author fields populated from a curated Bertha row which matches no TME term
+var curatedOnlyFields = append(append([]string{"uuid", "alternativeIdentifiers", "aliases", "shortDescription", "socialProfiles"}, curatedFields...), structuredNameFields...)
+
+func tmeProvenance(a author, loadedAt time.Time) authorProvenance {
+	p := authorProvenance{UUID: a.UUID, Fields: make(map[string]fieldProvenance)}
+	var termID string
+	if len(a.AlternativeIdentifiers.TME) > 0 {
+		termID = a.AlternativeIdentifiers.TME[0]
+	}
+	for _, f := range tmeFields {
+		p.Fields[f] = fieldProvenance{Source: sourceTME, SourceID: termID, LoadedAt: loadedAt}
+	}
+	return p
+}
+
+// curatedSources marks every one of the fields as coming from the curated spreadsheet
+func curatedSources(fields []string) map[string]string {
+	sources := make(map[string]string)
+	for _, f := range fields {
+		sources[f] = sourceCurated
+	}
+	return sources
+}
+
+// addCuratedProvenance records the source of fields set from the given row of the Bertha spreadsheet payload.
+// Rows are numbered from 1.
+func (p *authorProvenance) addCuratedProvenance(sources map[string]string, b berthaAuthor, row int, loadedAt time.Time) {
+	if p.Fields == nil {
+		p.Fields = make(map[string]fieldProvenance)
+	}
+	for field, source := range sources {
+		p.Fields[field] = fieldProvenance{Source: source, SourceID: b.TmeIdentifier, SourceRow: row, LoadedAt: loadedAt}
+	}
+}
@@ -0,0 +1,201 @@
+package authors
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// allowedElements are the FT body XML elements a description can have, with their allowed attributes
+var allowedElements = map[string]map[string]bool{
+	"p":          {},
+	"br":         {},
+	"a":          {"href": true, "title": true},
+	"strong":     {},
+	"em":         {},
+	"ul":         {},
+	"ol":         {},
+	"li":         {},
+	"h1":         {},
+	"h2":         {},
+	"h3":         {},
+	"blockquote": {},
+	"sub":        {},
+	"sup":        {},
+}
+
+// renamedElements are HTML elements with an FT body XML equivalent
+var renamedElements = map[string]string{"b": "strong", "i": "em"}
+
+// removedElements are dropped together with their content
+var removedElements = map[string]bool{
+	"script": true, "style": true, "iframe": true, "object": true, "embed": true, "form": true, "noscript": true,
+}
+
+// allowedSchemes are the schemes a link can have, links without one are relative
+var allowedSchemes = map[string]bool{"http": true, "https": true, "mailto": true}
+
+const ftBaseURL = "https://www.ft.com"
+
+var textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+var attributeEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+
+type sanitiser struct {
+	changes []string
+}
+
+// sanitiseDescription turns the HTML of a curated biography into well-formed FT body XML. Elements and
+// attributes which aren't allowed are removed, links are made absolute and every change made is returned.
+func sanitiseDescription(raw string) (string, []string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return raw, nil, nil
+	}
+	nodes, err := html.ParseFragment(strings.NewReader(raw), &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body})
+	if err != nil {
+		return "", nil, err
+	}
+	s := &sanitiser{}
+	var buf bytes.Buffer
+	for _, n := range nodes {
+		s.render(&buf, n)
+	}
+	sanitised := buf.String()
+	if err := checkWellFormed(sanitised); err != nil {
+		return "", nil, err
+	}
+	if len(s.changes) == 0 {
+		return sanitised, nil, nil
+	}
+	return sanitised, removeDuplicates(s.changes), nil
+}
+
+func (s *sanitiser) render(buf *bytes.Buffer, n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		buf.WriteString(textEscaper.Replace(n.Data))
+		return
+	case html.ElementNode:
+	case html.CommentNode:
+		s.changes = append(s.changes, "removed comment")
+		return
+	default:
+		s.renderChildren(buf, n)
+		return
+	}
+
+	name := n.Data
+	if removedElements[name] {
+		s.changes = append(s.changes, fmt.Sprintf("removed <%v> with its content", name))
+		return
+	}
+	if renamed, ok := renamedElements[name]; ok {
+		s.changes = append(s.changes, fmt.Sprintf("replaced <%v> with <%v>", name, renamed))
+		name = renamed
+	}
+	allowedAttributes, allowed := allowedElements[name]
+	if !allowed {
+		s.changes = append(s.changes, fmt.Sprintf("removed <%v> keeping its content", name))
+		s.renderChildren(buf, n)
+		return
+	}
+
+	var attributes []html.Attribute
+	for _, attr := range n.Attr {
+		if !allowedAttributes[attr.Key] {
+			s.changes = append(s.changes, fmt.Sprintf("removed attribute %v from <%v>", attr.Key, name))
+			continue
+		}
+		if attr.Key == "href" {
+			href, ok := s.rewriteLink(attr.Val)
+			if !ok {
+				s.renderChildren(buf, n)
+				return
+			}
+			attr.Val = href
+		}
+		attributes = append(attributes, attr)
+	}
+
+	buf.WriteString("<" + name)
+	for _, attr := range attributes {
+		buf.WriteString(" " + attr.Key + `="` + attributeEscaper.Replace(attr.Val) + `"`)
+	}
+	if n.FirstChild == nil && name == "br" {
+		buf.WriteString("/>")
+		return
+	}
+	buf.WriteString(">")
+	s.renderChildren(buf, n)
+	buf.WriteString("</" + name + ">")
+}
+
+func (s *sanitiser) renderChildren(buf *bytes.Buffer, n *html.Node) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		s.render(buf, c)
+	}
+}
+
+// rewriteLink makes a link absolute, and secure for FT links. Links with a scheme which isn't allowed are removed,
+// keeping their text. Tabs, newlines and other control characters are removed first, as browsers ignore them.
+func (s *sanitiser) rewriteLink(href string) (string, bool) {
+	trimmed := strings.TrimSpace(strings.Map(func(r rune) rune {
+		if r < ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, href))
+	lower := strings.ToLower(trimmed)
+	if scheme, ok := linkScheme(lower); ok && !allowedSchemes[scheme] {
+		s.changes = append(s.changes, fmt.Sprintf("removed link to %v", trimmed))
+		return "", false
+	}
+
+	rewritten := trimmed
+	switch {
+	case strings.HasPrefix(lower, "mailto:"), strings.HasPrefix(lower, "https://"):
+	case strings.HasPrefix(lower, "http://www.ft.com"), strings.HasPrefix(lower, "http://ft.com"):
+		rewritten = "https://" + trimmed[len("http://"):]
+	case strings.HasPrefix(lower, "//"):
+		rewritten = "https:" + trimmed
+	case strings.HasPrefix(lower, "/"):
+		rewritten = ftBaseURL + trimmed
+	case strings.HasPrefix(lower, "www."):
+		rewritten = "https://" + trimmed
+	}
+	if rewritten != href {
+		s.changes = append(s.changes, fmt.Sprintf("rewrote link %v to %v", href, rewritten))
+	}
+	return rewritten, true
+}
+
+// linkScheme is the scheme of a link, which is whatever comes before a colon found before any path, query or
+// fragment. Relative links have none.
+func linkScheme(link string) (string, bool) {
+	end := strings.IndexAny(link, "/?#")
+	if end < 0 {
+		end = len(link)
+	}
+	colon := strings.Index(link[:end], ":")
+	if colon < 0 {
+		return "", false
+	}
+	return link[:colon], true
+}
+
+func checkWellFormed(body string) error {
+	decoder := xml.NewDecoder(strings.NewReader("<body>" + body + "</body>"))
+	for {
+		_, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("Description is not well-formed XML: %v", err)
+		}
+	}
+}
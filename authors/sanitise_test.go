@@ -0,0 +1,83 @@
+package authors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitiseDescription(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		expected string
+		changes  []string
+	}{
+		{"Allowed elements are kept",
+			`<p>Martin Wolf is <strong>chief</strong> economics <em>commentator</em>.</p>`,
+			`<p>Martin Wolf is <strong>chief</strong> economics <em>commentator</em>.</p>`,
+			nil},
+		{"Empty",
+			"",
+			"",
+			nil},
+		{"Plain text is escaped",
+			`Profits & losses <3`,
+			`Profits &amp; losses &lt;3`,
+			nil},
+		{"Unclosed elements are closed",
+			`<p>One<p>Two<br>Three`,
+			`<p>One</p><p>Two<br/>Three</p>`,
+			nil},
+		{"Bold and italics are renamed",
+			`<b>Bold</b> and <i>italic</i>`,
+			`<strong>Bold</strong> and <em>italic</em>`,
+			[]string{"replaced <b> with <strong>", "replaced <i> with <em>"}},
+		{"Scripts and styles are removed",
+			`<style>p {color: red}</style><p>Bio<script>alert("hi")</script></p>`,
+			`<p>Bio</p>`,
+			[]string{"removed <style> with its content", "removed <script> with its content"}},
+		{"Unknown elements are unwrapped",
+			`<div><span class="x">Bio</span></div><!-- draft -->`,
+			`Bio`,
+			[]string{"removed <div> keeping its content", "removed <span> keeping its content", "removed comment"}},
+		{"Attributes which aren't allowed are removed",
+			`<p style="color: red" onclick="steal()">Bio</p>`,
+			`<p>Bio</p>`,
+			[]string{"removed attribute style from <p>", "removed attribute onclick from <p>"}},
+		{"Links are rewritten",
+			`<a href="/content/123" title="Column">column</a> <a href="http://www.ft.com/martin-wolf">page</a> <a href="//twitter.com/martinwolf_">tweets</a> <a href="http://example.com">site</a>`,
+			`<a href="https://www.ft.com/content/123" title="Column">column</a> <a href="https://www.ft.com/martin-wolf">page</a> <a href="https://twitter.com/martinwolf_">tweets</a> <a href="http://example.com">site</a>`,
+			[]string{"rewrote link /content/123 to https://www.ft.com/content/123", "rewrote link http://www.ft.com/martin-wolf to https://www.ft.com/martin-wolf",
+				"rewrote link //twitter.com/martinwolf_ to https://twitter.com/martinwolf_"}},
+		{"Unsafe links are removed",
+			`<a href="javascript:steal()">Click</a> <a href="mailto:martin.wolf@ft.com">Email</a>`,
+			`Click <a href="mailto:martin.wolf@ft.com">Email</a>`,
+			[]string{"removed link to javascript:steal()"}},
+		{"Links with encoded or split schemes are removed",
+			`<a href="java&#x09;script:alert(1)">Tab</a> <a href="jav&#x0A;ascript:alert(2)">Newline</a> <a href="&#106;avascript&colon;alert(3)">Entities</a> <a href=" java script:alert(4)">Space</a>`,
+			`Tab Newline Entities Space`,
+			[]string{"removed link to javascript:alert(1)", "removed link to javascript:alert(2)", "removed link to javascript:alert(3)", "removed link to java script:alert(4)"}},
+		{"Only http, https, mailto and relative links are kept",
+			`<a href="VBScript:msgbox(1)">VB</a> <a href="data:text/html,bio">Data</a> <a href="ftp://ftp.ft.com/bio">FTP</a> <a href="HTTPS://www.ft.com">FT</a> <a href="bio.html?at=10:30#top">Bio</a>`,
+			`VB Data FTP <a href="HTTPS://www.ft.com">FT</a> <a href="bio.html?at=10:30#top">Bio</a>`,
+			[]string{"removed link to VBScript:msgbox(1)", "removed link to data:text/html,bio", "removed link to ftp://ftp.ft.com/bio"}},
+		{"Attribute values are escaped",
+			`<a href='https://www.ft.com/?a=1&amp;b="2"'>Link</a>`,
+			`<a href="https://www.ft.com/?a=1&amp;b=&quot;2&quot;">Link</a>`,
+			nil},
+	}
+
+	for _, test := range tests {
+		sanitised, changes, err := sanitiseDescription(test.raw)
+		assert.NoError(t, err, test.name)
+		assert.Equal(t, test.expected, sanitised, test.name)
+		assert.Equal(t, test.changes, changes, test.name)
+		assert.NoError(t, checkWellFormed(sanitised), test.name)
+	}
+}
+
+func TestCheckWellFormed(t *testing.T) {
+	assert.NoError(t, checkWellFormed(`<p>Bio<br/></p>`))
+	assert.Error(t, checkWellFormed(`<p>Bio<br></p>`))
+}
@@ -6,18 +6,22 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
 	"sync"
 	"time"
 
-	"github.com/Financial-Times/tme-reader/tmereader"
-	"github.com/boltdb/bolt"
-	"github.com/jaytaylor/html2text"
-	"github.com/pborman/uuid"
 	log "github.com/sirupsen/logrus"
 )
 
 const (
-	cacheBucket = "author"
+	cacheBucket      = "author"
+	provenanceBucket = "provenance"
+	overrideBucket   = "override"
+	redirectBucket   = "redirect"
+	sourceBucket     = "source"
+
+	maxClosestMatches = 3
+	minMatchScore     = 0.6
 )
 
 type httpClient interface {
@@ -30,41 +34,127 @@ type AuthorService interface {
 	getAuthorLinks() (io.PipeReader, error)
 	getAuthorUUIDs() (io.PipeReader, error)
 	getAuthorByUUID(uuid string) (author, bool, error)
+	getCanonicalUUID(uuid string) (string, bool, error)
 	getCount() (int, error)
 	isInitialised() bool
 	isDataLoaded() bool
 	reloadDB() error
+	dryRunReload() (diffSummary, error)
+	getDryRunDiff() (io.PipeReader, bool, error)
+	getGenerations() ([]generation, error)
+	getDiff(from string, to string) (io.PipeReader, bool, error)
+	getSnapshot() (io.PipeReader, string, error)
+	retryFailedPages() error
+	getBlockedGeneration() (generation, bool)
+	activateBlockedGeneration() (generation, bool, error)
 	Shutdown() error
 	loadCuratedAuthors([]berthaAuthor) error
+	getUnmatchedCuratedAuthors() []unmatchedCuratedAuthor
+	getDuplicates() []duplicateGroup
+	checkUUIDs() ([]uuidMismatch, error)
+	getReloadReport() reloadReport
+	getAuthorProvenance(uuid string) (authorProvenance, bool, error)
+	getAuthorSource(uuid string) (authorSource, bool, error)
+	getOverrides() ([]authorOverride, error)
+	normaliseOverride(req overrideRequest) (overrideRequest, error)
+	saveOverride(uuid string, req overrideRequest, replace bool) (authorOverride, bool, error)
+	deleteOverride(uuid string) (bool, error)
 }
 
 type authorServiceImpl struct {
 	sync.RWMutex
-	repository    tmereader.Repository
-	baseURL       string
-	taxonomyName  string
-	maxTmeRecords int
-	initialised   bool
-	dataLoaded    bool
-	cacheFileName string
-	db            *bolt.DB
-	berthaURL     string
-	httpClient    httpClient
+	reloading       sync.Mutex
+	taxonomies      []Taxonomy
+	baseURL         string
+	maxTmeRecords   int
+	initialised     bool
+	dataLoaded      bool
+	store           AuthorStore
+	berthaURL       string
+	httpClient      httpClient
+	mergePolicy     MergePolicy
+	aliasOptions    AliasOptions
+	concordance     Concordance
+	imageOptions    ImageOptions
+	uuids           UUIDStrategy
+	paging          PagingOptions
+	guard           ReloadGuard
+	keptGenerations int
+	active          string
+	building        string
+	blocked         *generation
+	dryRun          *generation
+	next            curatedResults
+	served          curatedResults
+	report          reloadReport
+}
+
+// StartupOptions - what the authors are served from until the first reload is over
+type StartupOptions struct {
+	// SeedSnapshot is a file or http(s) URL of a snapshot, as returned by /transformers/authors/__snapshot
+	SeedSnapshot string
+	// MaxCacheAge is how long ago the active generation of an existing cache can have been loaded to be
+	// served right away. The cache is never served before a reload when 0.
+	MaxCacheAge time.Duration
+}
+
+// ServiceOptions - where the authors are read from, and how they are merged, kept and served
+type ServiceOptions struct {
+	BaseURL       string
+	MaxTmeRecords int
+	BerthaURL     string
+	// MergePolicy is the DefaultMergePolicy when nil
+	MergePolicy     MergePolicy
+	Aliases         AliasOptions
+	Concordance     Concordance
+	Images          ImageOptions
+	UUIDs           UUIDStrategy
+	Paging          PagingOptions
+	Guard           ReloadGuard
+	KeptGenerations int
+	Startup         StartupOptions
 }
 
 // NewAuthorService - create a new AuthorService
-func NewAuthorService(repo tmereader.Repository, baseURL string, taxonomyName string, maxTmeRecords int, cacheFileName string, berthaURL string, httpClient httpClient) AuthorService {
+func NewAuthorService(taxonomies []Taxonomy, store AuthorStore, httpClient httpClient, options ServiceOptions) AuthorService {
+	mergePolicy := options.MergePolicy
+	if mergePolicy == nil {
+		mergePolicy = DefaultMergePolicy()
+	}
+	startup := options.Startup
 	s := &authorServiceImpl{
-		repository:    repo,
-		baseURL:       baseURL,
-		taxonomyName:  taxonomyName,
-		maxTmeRecords: maxTmeRecords,
-		initialised:   true,
-		cacheFileName: cacheFileName,
-		berthaURL:     berthaURL,
-		httpClient:    httpClient}
+		taxonomies:      taxonomies,
+		baseURL:         options.BaseURL,
+		maxTmeRecords:   options.MaxTmeRecords,
+		initialised:     true,
+		store:           store,
+		berthaURL:       options.BerthaURL,
+		httpClient:      httpClient,
+		mergePolicy:     mergePolicy,
+		aliasOptions:    options.Aliases,
+		concordance:     options.Concordance,
+		imageOptions:    options.Images,
+		uuids:           options.UUIDs,
+		paging:          options.Paging,
+		guard:           options.Guard,
+		keptGenerations: options.KeptGenerations,
+		served:          newCuratedResults()}
 	s.setDataLoaded(false)
-	go func(service *authorServiceImpl) { service.reloadDB() }(s)
+	go func(service *authorServiceImpl) {
+		served := false
+		if startup.MaxCacheAge > 0 {
+			var err error
+			if served, err = service.serveCachedGeneration(startup.MaxCacheAge); err != nil {
+				log.Errorf("Error while reading the cached authors: [%v]", err.Error())
+			}
+		}
+		if !served && startup.SeedSnapshot != "" {
+			if err := service.importSnapshot(startup.SeedSnapshot); err != nil {
+				log.Errorf("Error while importing snapshot %v: [%v]", startup.SeedSnapshot, err.Error())
+			}
+		}
+		service.reloadDB()
+	}(s)
 	return s
 }
 
@@ -98,26 +188,23 @@ func (s *authorServiceImpl) Shutdown() error {
 	defer s.Unlock()
 	s.initialised = false
 	s.dataLoaded = false
-	if s.db == nil {
-		return errors.New("DB not open")
-	}
-	return s.db.Close()
+	return s.store.Close()
 }
 
 func (s *authorServiceImpl) getCount() (int, error) {
 	s.RLock()
 	defer s.RUnlock()
-	if !s.isDataLoaded() {
+	if !s.dataLoaded {
 		return 0, nil
 	}
 
 	var count int
-	err := s.db.View(func(tx *bolt.Tx) error {
-		bucket := tx.Bucket([]byte(cacheBucket))
-		if bucket == nil {
-			return fmt.Errorf("Bucket %v not found!", cacheBucket)
+	err := s.store.View(func(tx StoreTx) error {
+		gen, err := generationOf(tx, s.active)
+		if err != nil {
+			return err
 		}
-		count = bucket.Stats().KeyN
+		count = gen.Bucket(cacheBucket).Count()
 		return nil
 	})
 	return count, err
@@ -129,9 +216,12 @@ func (s *authorServiceImpl) getAuthors() (io.PipeReader, error) {
 	go func() {
 		defer s.RUnlock()
 		defer pw.Close()
-		s.db.View(func(tx *bolt.Tx) error {
-			b := tx.Bucket([]byte(cacheBucket))
-			c := b.Cursor()
+		s.store.View(func(tx StoreTx) error {
+			gen, err := generationOf(tx, s.active)
+			if err != nil {
+				return err
+			}
+			c := gen.Bucket(cacheBucket).Cursor()
 			for k, v := c.First(); k != nil; k, v = c.Next() {
 				if _, err := pw.Write(v); err != nil {
 					return err
@@ -150,9 +240,12 @@ func (s *authorServiceImpl) getAuthorUUIDs() (io.PipeReader, error) {
 	go func() {
 		defer s.RUnlock()
 		defer pw.Close()
-		s.db.View(func(tx *bolt.Tx) error {
-			b := tx.Bucket([]byte(cacheBucket))
-			c := b.Cursor()
+		s.store.View(func(tx StoreTx) error {
+			gen, err := generationOf(tx, s.active)
+			if err != nil {
+				return err
+			}
+			c := gen.Bucket(cacheBucket).Cursor()
 			encoder := json.NewEncoder(pw)
 			for k, _ := c.First(); k != nil; k, _ = c.Next() {
 				if k == nil {
@@ -176,9 +269,12 @@ func (s *authorServiceImpl) getAuthorLinks() (io.PipeReader, error) {
 		defer s.RUnlock()
 		defer pw.Close()
 		io.WriteString(pw, "[")
-		s.db.View(func(tx *bolt.Tx) error {
-			b := tx.Bucket([]byte(cacheBucket))
-			c := b.Cursor()
+		s.store.View(func(tx StoreTx) error {
+			gen, err := generationOf(tx, s.active)
+			if err != nil {
+				return err
+			}
+			c := gen.Bucket(cacheBucket).Cursor()
 			encoder := json.NewEncoder(pw)
 			var k []byte
 			k, _ = c.First()
@@ -204,12 +300,16 @@ func (s *authorServiceImpl) getAuthorLinks() (io.PipeReader, error) {
 func (s *authorServiceImpl) getAuthorByUUID(uuid string) (author, bool, error) {
 	s.RLock()
 	defer s.RUnlock()
+	if !s.dataLoaded {
+		return author{}, false, nil
+	}
 	var cachedValue []byte
-	err := s.db.View(func(tx *bolt.Tx) error {
-		bucket := tx.Bucket([]byte(cacheBucket))
-		if bucket == nil {
-			return fmt.Errorf("Bucket %v not found!", cacheBucket)
+	err := s.store.View(func(tx StoreTx) error {
+		gen, err := generationOf(tx, s.active)
+		if err != nil {
+			return err
 		}
+		bucket := gen.Bucket(cacheBucket)
 		cachedValue = bucket.Get([]byte(uuid))
 		return nil
 	})
@@ -231,95 +331,293 @@ func (s *authorServiceImpl) getAuthorByUUID(uuid string) (author, bool, error) {
 	return cachedAuthor, true, nil
 }
 
+// getCanonicalUUID returns the UUID of the author a duplicate author was merged into
+func (s *authorServiceImpl) getCanonicalUUID(uuid string) (string, bool, error) {
+	s.RLock()
+	defer s.RUnlock()
+	if !s.dataLoaded {
+		return "", false, nil
+	}
+	var canonicalUUID []byte
+	err := s.store.View(func(tx StoreTx) error {
+		gen, err := generationOf(tx, s.active)
+		if err != nil {
+			return err
+		}
+		bucket := gen.Bucket(redirectBucket)
+		canonicalUUID = bucket.Get([]byte(uuid))
+		return nil
+	})
+	if err != nil {
+		log.Errorf("ERROR reading redirect from cache file for [%v]: %v", uuid, err.Error())
+		return "", false, err
+	}
+	return string(canonicalUUID), len(canonicalUUID) > 0, nil
+}
+
+func (s *authorServiceImpl) getAuthorProvenance(uuid string) (authorProvenance, bool, error) {
+	s.RLock()
+	defer s.RUnlock()
+	if !s.dataLoaded {
+		return authorProvenance{}, false, nil
+	}
+	var cachedValue []byte
+	err := s.store.View(func(tx StoreTx) error {
+		gen, err := generationOf(tx, s.active)
+		if err != nil {
+			return err
+		}
+		bucket := gen.Bucket(provenanceBucket)
+		cachedValue = bucket.Get([]byte(uuid))
+		return nil
+	})
+
+	if err != nil {
+		log.Errorf("ERROR reading provenance from cache file for [%v]: %v", uuid, err.Error())
+		return authorProvenance{}, false, err
+	}
+	if len(cachedValue) == 0 {
+		return authorProvenance{}, false, nil
+	}
+
+	var p authorProvenance
+	if err := json.Unmarshal(cachedValue, &p); err != nil {
+		log.Errorf("ERROR unmarshalling cached provenance for [%v]: %v.", uuid, err.Error())
+		return authorProvenance{}, true, err
+	}
+	return p, true, nil
+}
+
+func (s *authorServiceImpl) getAuthorSource(uuid string) (authorSource, bool, error) {
+	s.RLock()
+	defer s.RUnlock()
+	if !s.dataLoaded {
+		return authorSource{}, false, nil
+	}
+	var src authorSource
+	found := false
+	err := s.store.View(func(tx StoreTx) error {
+		gen, err := generationOf(tx, s.active)
+		if err != nil {
+			return err
+		}
+		bucket := gen.Bucket(sourceBucket)
+		found = bucket.Get([]byte(uuid)) != nil
+		src, err = readSource(bucket, uuid)
+		return err
+	})
+	if err != nil {
+		log.Errorf("ERROR reading raw sources from cache file for [%v]: %v", uuid, err.Error())
+		return authorSource{}, found, err
+	}
+	return src, found, nil
+}
+
 func (s *authorServiceImpl) openDB() error {
 	s.Lock()
 	defer s.Unlock()
-	log.Infof("Opening database '%v'.", s.cacheFileName)
-	if s.db == nil {
-		var err error
-		if s.db, err = bolt.Open(s.cacheFileName, 0600, &bolt.Options{Timeout: 1 * time.Second}); err != nil {
-			log.Errorf("ERROR opening cache file for init: %v.", err.Error())
-			return err
-		}
+	if err := s.store.Open(); err != nil {
+		return err
 	}
-	return s.createCacheBucket()
+	return s.readActiveGeneration()
 }
 
 func (s *authorServiceImpl) reloadDB() error {
-	s.setDataLoaded(false)
+	_, err := s.reload(false)
+	return err
+}
+
+// dryRunReload loads the authors into a new generation without activating it and sums up how it differs
+// from the active generation
+func (s *authorServiceImpl) dryRunReload() (diffSummary, error) {
+	g, err := s.reload(true)
+	if g.ID == "" {
+		return diffSummary{}, err
+	}
+	s.RLock()
+	defer s.RUnlock()
+	summary := newDiffSummary(s.active, g.ID)
+	if diffErr := s.store.View(func(tx StoreTx) error {
+		return diffGenerations(tx, s.active, g.ID, func(d authorDiff) error {
+			summary.summarise(d)
+			return nil
+		})
+	}); diffErr != nil {
+		return diffSummary{}, diffErr
+	}
+	return summary, err
+}
+
+// getDryRunDiff streams how every author of the last dry run differs from the active generation, as NDJSON
+func (s *authorServiceImpl) getDryRunDiff() (io.PipeReader, bool, error) {
+	s.RLock()
+	if s.dryRun == nil {
+		s.RUnlock()
+		return io.PipeReader{}, false, nil
+	}
+	return s.streamDiff(s.active, s.dryRun.ID), true, nil
+}
+
+// reload loads the authors into a new generation, which is activated unless it's a dry run or the reload
+// guard blocks it
+func (s *authorServiceImpl) reload(dryRun bool) (generation, error) {
+	s.reloading.Lock()
+	defer s.reloading.Unlock()
+	if !dryRun {
+		defer s.serveActiveGeneration()
+	}
+	startedAt := time.Now()
+	s.startReport(startedAt, dryRun)
+	defer s.finishReport()
+	defer recordReload(s.httpClient, startedAt)()
+	if err := s.openDB(); err != nil {
+		s.setInitialised(false)
+		return generation{}, err
+	}
+	if err := s.newGeneration(startedAt, dryRun); err != nil {
+		log.Errorf("Error while creating a generation of authors: [%v]", err.Error())
+		return generation{}, err
+	}
 	err := s.loadDB()
 	if err != nil {
 		log.Errorf("Error while creating AuthorService: [%v]", err.Error())
-		return err
+		s.abandonGeneration()
+		return generation{}, err
+	}
+	err = s.applyCuratedData(nil)
+	g, finishErr := s.finishGeneration()
+	if finishErr != nil {
+		log.Errorf("Error while activating the generation of authors: [%v]", finishErr.Error())
+		return generation{}, finishErr
 	}
-	var bAuthors []berthaAuthor
+	if !dryRun {
+		s.compact()
+	}
+	return g, err
+}
+
+// serveActiveGeneration serves the active generation, if any, once a reload is over
+func (s *authorServiceImpl) serveActiveGeneration() {
+	s.Lock()
+	defer s.Unlock()
+	s.dataLoaded = s.initialised && s.active != ""
+}
 
-	bAuthors, err = s.getBerthaAuthors(s.berthaURL)
+// applyCuratedData merges the curated authors, overrides and concordance into the TME authors and looks for duplicates.
+// Only the authors with a UUID in only get the curated data, or every author when only is nil.
+func (s *authorServiceImpl) applyCuratedData(only map[string]bool) error {
+	bAuthors, err := s.getBerthaAuthors(s.berthaURL)
 	if err != nil {
 		log.Errorf("Error on Bertha load: [%v]", err.Error())
-		return err
 	} else {
-		err = s.loadCuratedAuthors(bAuthors)
+		err = s.mergeCuratedAuthors(bAuthors, only)
 		if err != nil {
 			log.Errorf("Error while loading in the curated authors: [%v]", err.Error())
 		}
 	}
 
+	if overrideErr := s.applyOverrides(only); overrideErr != nil {
+		log.Errorf("Error while applying the author overrides: [%v]", overrideErr.Error())
+		if err == nil {
+			err = overrideErr
+		}
+	}
+
+	if concordanceErr := s.applyConcordance(only); concordanceErr != nil {
+		log.Errorf("Error while merging the concorded authors: [%v]", concordanceErr.Error())
+		if err == nil {
+			err = concordanceErr
+		}
+	}
+
+	if duplicatesErr := s.detectDuplicates(); duplicatesErr != nil {
+		log.Errorf("Error while detecting duplicate authors: [%v]", duplicatesErr.Error())
+		if err == nil {
+			err = duplicatesErr
+		}
+	}
 	return err
 }
 
 func (s *authorServiceImpl) loadDB() error {
 	var wg sync.WaitGroup
 	log.Info("Loading DB...")
-	c := make(chan []author)
-	go s.processAuthors(c, &wg)
+	c := make(chan []transformedTerm)
+	go s.processAuthors(c, &wg, s.building, time.Now(), make(map[string]string), nil)
 	defer func(w *sync.WaitGroup) {
 		close(c)
 		w.Wait()
 	}(&wg)
 
-	if err := s.openDB(); err != nil {
-		s.setInitialised(false)
-		return err
-	}
+	for _, taxonomy := range s.taxonomies {
+		log.Infof("Fetching authors from TME taxonomy %v.", taxonomy.Name)
+		responseCount := 0
+		for {
+			terms, err := s.fetchPage(taxonomy, responseCount)
+			if err != nil {
+				if err := s.addFailedPage(taxonomy.Name, responseCount, err); err != nil {
+					return err
+				}
+				responseCount += s.maxTmeRecords
+				continue
+			}
+			if len(terms) < 1 {
+				log.Infof("Finished fetching authors from TME taxonomy %v.", taxonomy.Name)
+				break
+			}
 
-	responseCount := 0
-	for {
-		terms, err := s.repository.GetTmeTermsFromIndex(responseCount)
-		if err != nil {
-			return err
+			wg.Add(1)
+			s.processTerms(terms, taxonomy.Name, c)
+			responseCount += s.maxTmeRecords
 		}
-		if len(terms) < 1 {
-			log.Info("Finished fetching authors from TME. Waiting subroutines to terminate.")
-			break
-		}
-
-		wg.Add(1)
-		s.processTerms(terms, c)
-		responseCount += s.maxTmeRecords
 	}
+	log.Info("Finished fetching authors from TME. Waiting subroutines to terminate.")
 	return nil
 }
 
-func (s *authorServiceImpl) processTerms(terms []interface{}, c chan<- []author) {
+// transformedTerm is an author transformed from a TME term, with the raw term
+type transformedTerm struct {
+	author author
+	source tmeSource
+}
+
+func (s *authorServiceImpl) processTerms(terms []interface{}, taxonomyName string, c chan<- []transformedTerm) {
 	log.Info("Processing terms...")
-	var cacheToBeWritten []author
+	var cacheToBeWritten []transformedTerm
 	for _, iTerm := range terms {
 		t := iTerm.(term)
-		cacheToBeWritten = append(cacheToBeWritten, transformAuthor(t, s.taxonomyName))
+		a := transformAuthor(t, taxonomyName, s.aliasOptions, s.uuids)
+		cacheToBeWritten = append(cacheToBeWritten, transformedTerm{author: a, source: tmeSourceOf(t, a.AlternativeIdentifiers.TME[0], taxonomyName)})
 	}
 	c <- cacheToBeWritten
 }
 
-func (s *authorServiceImpl) processAuthors(c <-chan []author, wg *sync.WaitGroup) {
+// processAuthors stores the batches of authors it receives into a generation. identities indexes the authors
+// already stored by the identity of their TME terms. The UUIDs of the authors it adds are recorded in added, unless
+// it's nil.
+func (s *authorServiceImpl) processAuthors(c <-chan []transformedTerm, wg *sync.WaitGroup, generation string, loadedAt time.Time, identities map[string]string, added map[string]bool) {
 	for authors := range c {
 		log.Infof("Processing batch of %v authors.", len(authors))
-		if err := s.db.Batch(func(tx *bolt.Tx) error {
-			bucket := tx.Bucket([]byte(cacheBucket))
-			if bucket == nil {
-				return fmt.Errorf("Cache bucket [%v] not found!", cacheBucket)
+		if err := s.store.Batch(func(tx StoreTx) error {
+			gen, err := generationOf(tx, generation)
+			if err != nil {
+				return err
 			}
-			for _, anAuthor := range authors {
+			bucket := gen.Bucket(cacheBucket)
+			pBucket := gen.Bucket(provenanceBucket)
+			sBucket := gen.Bucket(sourceBucket)
+			for _, transformed := range authors {
+				anAuthor := transformed.author
+				uuid, merged, err := mergeSameIdentity(gen, identities, anAuthor)
+				if err != nil {
+					return err
+				}
+				if err := addTmeSource(sBucket, uuid, transformed.source); err != nil {
+					return err
+				}
+				if merged {
+					continue
+				}
 				marshalledAuthor, err := json.Marshal(anAuthor)
 				if err != nil {
 					return err
@@ -328,6 +626,12 @@ func (s *authorServiceImpl) processAuthors(c <-chan []author, wg *sync.WaitGroup
 				if err != nil {
 					return err
 				}
+				if err = putProvenance(pBucket, tmeProvenance(anAuthor, loadedAt)); err != nil {
+					return err
+				}
+				if added != nil {
+					added[anAuthor.UUID] = true
+				}
 			}
 			return nil
 		}); err != nil {
@@ -337,23 +641,63 @@ func (s *authorServiceImpl) processAuthors(c <-chan []author, wg *sync.WaitGroup
 	}
 
 	log.Info("Finished processing all authors.")
-	if s.isInitialised() {
-		s.setDataLoaded(true)
+}
+
+// mergeSameIdentity merges an author into the author already loaded for the same TME term from another taxonomy,
+// or with the same UUID, keeping a redirect from its own UUID. It returns the UUID the author is kept under and
+// whether it was merged.
+func mergeSameIdentity(gen StoreGeneration, identities map[string]string, a author) (string, bool, error) {
+	bucket := gen.Bucket(cacheBucket)
+	var identity string
+	if len(a.AlternativeIdentifiers.TME) > 0 {
+		identity = termIdentity(a.AlternativeIdentifiers.TME[0])
+	}
+	existingUUID, ok := identities[identity]
+	if !ok || identity == "" {
+		existingUUID = a.UUID
+	}
+	cachedAuthor := bucket.Get([]byte(existingUUID))
+	if cachedAuthor == nil {
+		if identity != "" {
+			identities[identity] = a.UUID
+		}
+		return a.UUID, false, nil
+	}
+
+	var existing author
+	if err := json.Unmarshal(cachedAuthor, &existing); err != nil {
+		return "", false, err
+	}
+	log.Infof("Merging author %v [%v] into %v, which has the same identity.", a.PrefLabel, a.UUID, existingUUID)
+	marshalledAuthor, err := json.Marshal(mergeSecondary(existing, a))
+	if err != nil {
+		return "", false, err
 	}
+	if err := bucket.Put([]byte(existingUUID), marshalledAuthor); err != nil {
+		return "", false, err
+	}
+	if a.UUID != existingUUID {
+		if err := gen.Bucket(redirectBucket).Put([]byte(a.UUID), []byte(existingUUID)); err != nil {
+			return "", false, err
+		}
+	}
+	return existingUUID, true, nil
 }
 
-func (s *authorServiceImpl) createCacheBucket() error {
-	return s.db.Update(func(tx *bolt.Tx) error {
-		if tx.Bucket([]byte(cacheBucket)) != nil {
-			log.Infof("Deleting bucket '%v'.", cacheBucket)
-			if err := tx.DeleteBucket([]byte(cacheBucket)); err != nil {
-				log.Warnf("Cache bucket [%v] could not be deleted.", cacheBucket)
-			}
+// readIdentities indexes the authors of a generation by the identity of their TME terms, as processAuthors does
+// while it stores them
+func (s *authorServiceImpl) readIdentities(generation string) (map[string]string, error) {
+	identities := make(map[string]string)
+	cachedAuthors, err := s.readCachedAuthors(generation)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range cachedAuthors {
+		for _, tmeIdentifier := range a.AlternativeIdentifiers.TME {
+			identities[termIdentity(tmeIdentifier)] = a.UUID
 		}
-		log.Infof("Creating bucket '%s'.", cacheBucket)
-		_, err := tx.CreateBucket([]byte(cacheBucket))
-		return err
-	})
+	}
+	return identities, nil
 }
 
 func (s *authorServiceImpl) getBerthaAuthors(berthaURL string) ([]berthaAuthor, error) {
@@ -363,67 +707,635 @@ func (s *authorServiceImpl) getBerthaAuthors(berthaURL string) ([]berthaAuthor,
 	}
 
 	res, err := s.httpClient.Do(req)
-	var bAuthors []berthaAuthor
-	err = json.NewDecoder(res.Body).Decode(&bAuthors)
-	return bAuthors, err
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, fmt.Errorf("Fetching the curated authors from %v answered %v", berthaURL, res.Status)
+	}
+	var rows []json.RawMessage
+	if err = json.NewDecoder(res.Body).Decode(&rows); err != nil {
+		return nil, err
+	}
+	bAuthors := make([]berthaAuthor, len(rows))
+	for i, row := range rows {
+		if err := json.Unmarshal(row, &bAuthors[i]); err != nil {
+			return nil, err
+		}
+		bAuthors[i].Raw = row
+	}
+	return bAuthors, nil
 }
 
 func (s *authorServiceImpl) loadCuratedAuthors(bAuthors []berthaAuthor) error {
+	return s.mergeCuratedAuthors(bAuthors, nil)
+}
+
+// mergeCuratedAuthors merges the curated authors into the authors with a UUID in only, or into every author when
+// only is nil. What it finds about the authors it merged adds to what was found before.
+func (s *authorServiceImpl) mergeCuratedAuthors(bAuthors []berthaAuthor, only map[string]bool) error {
 	s.Lock()
 	defer s.Unlock()
-	err := s.db.Batch(func(tx *bolt.Tx) error {
-		bucket := tx.Bucket([]byte(cacheBucket))
-		if bucket == nil {
-			return fmt.Errorf("Cache bucket [%v] not found!", cacheBucket)
+	var unmatched []unmatchedCuratedAuthor
+	var descriptions []descriptionReport
+	var invalidProfiles []socialProfileReport
+	var invalidImages []imageReport
+	var matches int
+	loadedAt := time.Now()
+	err := s.store.Batch(func(tx StoreTx) error {
+		unmatched = nil
+		matches = 0
+		descriptions = []descriptionReport{}
+		invalidProfiles = []socialProfileReport{}
+		invalidImages = []imageReport{}
+		gen, err := generationOf(tx, s.building)
+		if err != nil {
+			return err
 		}
+		bucket := gen.Bucket(cacheBucket)
+		pBucket := gen.Bucket(provenanceBucket)
+		sBucket := gen.Bucket(sourceBucket)
 
-		for _, b := range bAuthors {
-			berthaUUID := uuid.NewMD5(uuid.UUID{}, []byte(b.TmeIdentifier)).String()
+		for i, b := range bAuthors {
+			berthaUUID := s.uuids.uuidOf(b.TmeIdentifier)
+			if only != nil && !only[berthaUUID] {
+				continue
+			}
+			if b.Raw != nil {
+				src, err := readSource(sBucket, berthaUUID)
+				if err != nil {
+					return err
+				}
+				src.Bertha = b.Raw
+				if err := putSource(sBucket, src); err != nil {
+					return err
+				}
+			}
+			var report descriptionReport
+			b, report = sanitiseBiography(b, berthaUUID)
+			if len(report.Changes) > 0 || report.Error != "" {
+				descriptions = append(descriptions, report)
+			}
+			_, invalid := curatedSocialProfiles(b, berthaUUID)
+			invalidProfiles = append(invalidProfiles, invalid...)
+			var image *imageReport
+			if b, image = s.checkImage(b, berthaUUID); image != nil {
+				invalidImages = append(invalidImages, *image)
+			}
 			cachedAuthor := bucket.Get([]byte(berthaUUID))
 			var a author
+			p := authorProvenance{UUID: berthaUUID}
 			if cachedAuthor == nil {
 				log.Warnf("Curated author %s [%s] was not found in cache.  Adding without V1 information.", b.Name, berthaUUID)
-				a, _ = berthaToAuthor(b)
+				unmatched = append(unmatched, unmatchedCuratedAuthor{Name: b.Name, TmeIdentifier: b.TmeIdentifier, UUID: berthaUUID})
+				a, _ = berthaToAuthor(b, s.uuids)
+				p.addCuratedProvenance(curatedSources(curatedOnlyFields), b, i+1, loadedAt)
 			} else {
+				matches++
 				json.Unmarshal(cachedAuthor, &a)
-				a, _ = addBerthaInformation(a, b)
+				var sources map[string]string
+				a, sources, _ = addBerthaInformation(a, b, s.mergePolicy, s.uuids)
+				if cachedProvenance := pBucket.Get([]byte(berthaUUID)); cachedProvenance != nil {
+					json.Unmarshal(cachedProvenance, &p)
+				}
+				p.addCuratedProvenance(sources, b, i+1, loadedAt)
 			}
+			a.Aliases = completeAliases(a.Aliases, a.Name, s.aliasOptions)
 
 			newCachedVersion, _ := json.Marshal(a)
 			bucket.Put([]byte(berthaUUID), newCachedVersion)
+			if err := putProvenance(pBucket, p); err != nil {
+				return err
+			}
 		}
 
+		return findClosestMatches(bucket, unmatched)
+	})
+	if err != nil {
+		return err
+	}
+	if only == nil {
+		s.next.unmatched = unmatched
+		s.next.curated = curatedConcordance(bAuthors)
+		s.next.matches = 0
+		s.next.descriptions = []descriptionReport{}
+		s.next.socialProfiles = []socialProfileReport{}
+		s.next.images = []imageReport{}
+	}
+	s.next.matches += matches
+	s.next.descriptions = append(s.next.descriptions, descriptions...)
+	s.next.socialProfiles = append(s.next.socialProfiles, invalidProfiles...)
+	s.next.images = append(s.next.images, invalidImages...)
+	return nil
+}
+
+func (s *authorServiceImpl) getUnmatchedCuratedAuthors() []unmatchedCuratedAuthor {
+	s.RLock()
+	defer s.RUnlock()
+	return s.served.unmatched
+}
+
+// getReloadReport reports the last reload, with what loading the curated authors being served found
+func (s *authorServiceImpl) getReloadReport() reloadReport {
+	s.RLock()
+	defer s.RUnlock()
+	r := s.report
+	r.Descriptions = s.served.descriptions
+	r.SocialProfiles = s.served.socialProfiles
+	r.Images = s.served.images
+	return r
+}
+
+func (s *authorServiceImpl) startReport(startedAt time.Time, dryRun bool) {
+	s.Lock()
+	defer s.Unlock()
+	s.report = reloadReport{StartedAt: startedAt, DryRun: dryRun, FailedPages: []failedPage{}}
+}
+
+func (s *authorServiceImpl) finishReport() {
+	s.Lock()
+	defer s.Unlock()
+	finishedAt := time.Now()
+	s.report.FinishedAt = &finishedAt
+}
+
+func (s *authorServiceImpl) getDuplicates() []duplicateGroup {
+	s.RLock()
+	defer s.RUnlock()
+	return s.served.duplicates
+}
+
+// detectDuplicates looks for authors in the cache which are likely to be the same person under different TME terms.
+// They are compared without holding the lock, so the authors being served can still be read meanwhile.
+func (s *authorServiceImpl) detectDuplicates() error {
+	s.RLock()
+	building := s.building
+	s.RUnlock()
+	cachedAuthors, err := s.readCachedAuthors(building)
+	if err != nil {
+		return err
+	}
+	duplicates := findDuplicates(cachedAuthors)
+	log.Infof("Found %d groups of possible duplicate authors.", len(duplicates))
+
+	s.Lock()
+	defer s.Unlock()
+	s.next.duplicates = duplicates
+	return nil
+}
+
+// checkUUIDs lists the authors in the cache whose UUID doesn't match the UUID strategy
+func (s *authorServiceImpl) checkUUIDs() ([]uuidMismatch, error) {
+	s.RLock()
+	defer s.RUnlock()
+	cachedAuthors, err := s.readCachedAuthors(s.active)
+	if err != nil {
+		return nil, err
+	}
+	return s.uuids.uuidMismatches(cachedAuthors), nil
+}
+
+func (s *authorServiceImpl) readCachedAuthors(generation string) ([]author, error) {
+	var cachedAuthors []author
+	err := s.store.View(func(tx StoreTx) error {
+		gen, err := generationOf(tx, generation)
+		if err != nil {
+			return err
+		}
+		return gen.Bucket(cacheBucket).ForEach(func(k, v []byte) error {
+			var a author
+			if err := json.Unmarshal(v, &a); err != nil {
+				return err
+			}
+			cachedAuthors = append(cachedAuthors, a)
+			return nil
+		})
+	})
+	return cachedAuthors, err
+}
+
+func (s *authorServiceImpl) getOverrides() ([]authorOverride, error) {
+	s.RLock()
+	defer s.RUnlock()
+	overrides := []authorOverride{}
+	err := s.store.View(func(tx StoreTx) error {
+		bucket := tx.Bucket(overrideBucket)
+		if bucket == nil {
+			return fmt.Errorf("Bucket %v not found!", overrideBucket)
+		}
+		return bucket.ForEach(func(k, v []byte) error {
+			var o authorOverride
+			if err := json.Unmarshal(v, &o); err != nil {
+				return err
+			}
+			overrides = append(overrides, o)
+			return nil
+		})
+	})
+	return overrides, err
+}
+
+// normaliseOverride sanitises and rewrites the fields of an override request like those of the curated authors
+func (s *authorServiceImpl) normaliseOverride(req overrideRequest) (overrideRequest, error) {
+	return normaliseOverrideRequest(req, s.imageOptions)
+}
+
+func (s *authorServiceImpl) saveOverride(uuid string, req overrideRequest, replace bool) (authorOverride, bool, error) {
+	s.Lock()
+	defer s.Unlock()
+	var o authorOverride
+	found := false
+	if !s.dataLoaded {
+		return o, found, nil
+	}
+	err := s.store.Update(func(tx StoreTx) error {
+		gen, err := generationOf(tx, s.active)
+		if err != nil {
+			return err
+		}
+		if gen.Bucket(cacheBucket).Get([]byte(uuid)) == nil {
+			return nil
+		}
+		found = true
+		bucket := tx.Bucket(overrideBucket)
+		var existing authorOverride
+		if cachedOverride := bucket.Get([]byte(uuid)); cachedOverride != nil {
+			if err := json.Unmarshal(cachedOverride, &existing); err != nil {
+				return err
+			}
+		}
+		o = updateOverride(existing, uuid, req, replace, time.Now())
+		removed := removedFields(existing, o)
+		if len(o.Fields) == 0 {
+			log.Infof("Override of %v by %v left no fields, removing it: %v", uuid, o.User, o.Reason)
+			if err := bucket.Delete([]byte(uuid)); err != nil {
+				return err
+			}
+			return s.reapplyOverride(tx, uuid, removed, o)
+		}
+		marshalledOverride, err := json.Marshal(o)
+		if err != nil {
+			return err
+		}
+		if err := bucket.Put([]byte(uuid), marshalledOverride); err != nil {
+			return err
+		}
+		log.Infof("Author %v overridden by %v: %v", uuid, o.User, o.Reason)
+		return s.reapplyOverride(tx, uuid, removed, o)
+	})
+	return o, found, err
+}
+
+func (s *authorServiceImpl) deleteOverride(uuid string) (bool, error) {
+	s.Lock()
+	defer s.Unlock()
+	found := false
+	err := s.store.Update(func(tx StoreTx) error {
+		bucket := tx.Bucket(overrideBucket)
+		cachedOverride := bucket.Get([]byte(uuid))
+		if cachedOverride == nil {
+			return nil
+		}
+		found = true
+		var o authorOverride
+		if err := json.Unmarshal(cachedOverride, &o); err != nil {
+			return err
+		}
+		log.Infof("Removing override of author %v.", uuid)
+		if err := bucket.Delete([]byte(uuid)); err != nil {
+			return err
+		}
+		return s.reapplyOverride(tx, uuid, removedFields(o, authorOverride{}), authorOverride{})
+	})
+	return found, err
+}
+
+// reapplyOverride restores the fields an override no longer sets and applies what's left of it to the author in
+// every generation which is or can still be served: the active one, the one loaded by the last reload and the
+// blocked one. A reload applies the overrides before it finishes, so those it didn't see are applied here.
+func (s *authorServiceImpl) reapplyOverride(tx StoreTx, uuid string, removed []string, o authorOverride) error {
+	for _, id := range s.servableGenerations() {
+		gen := tx.Generation(id)
+		if gen == nil {
+			continue
+		}
+		if err := s.restoreAuthor(gen, uuid, removed); err != nil {
+			return err
+		}
+		if len(o.Fields) == 0 {
+			continue
+		}
+		if err := overrideAuthor(gen, o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *authorServiceImpl) servableGenerations() []string {
+	var ids []string
+	for _, id := range []string{s.active, s.building, s.blockedID()} {
+		if id != "" && !contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func (s *authorServiceImpl) blockedID() string {
+	if s.blocked == nil {
+		return ""
+	}
+	return s.blocked.ID
+}
+
+// restoreAuthor derives the fields of an author which are no longer overridden from its sources again
+func (s *authorServiceImpl) restoreAuthor(gen StoreGeneration, uuid string, removed []string) error {
+	if len(removed) == 0 {
+		return nil
+	}
+	bucket := gen.Bucket(cacheBucket)
+	cachedAuthor := bucket.Get([]byte(uuid))
+	if cachedAuthor == nil {
 		return nil
+	}
+	var a author
+	if err := json.Unmarshal(cachedAuthor, &a); err != nil {
+		return err
+	}
+	pBucket := gen.Bucket(provenanceBucket)
+	p := authorProvenance{UUID: uuid}
+	if cachedProvenance := pBucket.Get([]byte(uuid)); cachedProvenance != nil {
+		if err := json.Unmarshal(cachedProvenance, &p); err != nil {
+			return err
+		}
+	}
+	src, err := readSource(gen.Bucket(sourceBucket), uuid)
+	if err != nil {
+		return err
+	}
+	derived, derivedProvenance, err := s.deriveAuthor(src, curatedRow(p), p.Fields["uuid"].LoadedAt)
+	if err != nil {
+		return err
+	}
+	if a, p, err = restoreFields(a, p, derived, derivedProvenance, removed); err != nil {
+		return err
+	}
+	log.Infof("Restored %v of author %v.", removed, uuid)
+	marshalledAuthor, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	if err := bucket.Put([]byte(uuid), marshalledAuthor); err != nil {
+		return err
+	}
+	return putProvenance(pBucket, p)
+}
+
+// applyOverrides applies the stored overrides to the freshly loaded authors with a UUID in only, or to every
+// author when only is nil
+func (s *authorServiceImpl) applyOverrides(only map[string]bool) error {
+	s.Lock()
+	defer s.Unlock()
+	return s.store.Update(func(tx StoreTx) error {
+		gen, err := generationOf(tx, s.building)
+		if err != nil {
+			return err
+		}
+		return tx.Bucket(overrideBucket).ForEach(func(k, v []byte) error {
+			var o authorOverride
+			if err := json.Unmarshal(v, &o); err != nil {
+				return err
+			}
+			if only != nil && !only[o.UUID] {
+				return nil
+			}
+			return overrideAuthor(gen, o)
+		})
 	})
+}
 
-	return err
+// applyConcordance merges the duplicate authors of the configured and curated concordances into their
+// canonical authors and records where they went. When only isn't nil, only the concorded authors of which one
+// has a UUID in only are merged.
+func (s *authorServiceImpl) applyConcordance(only map[string]bool) error {
+	s.Lock()
+	defer s.Unlock()
+	concordance := s.concordance.with(s.next.curated)
+	return s.store.Update(func(tx StoreTx) error {
+		gen, err := generationOf(tx, s.building)
+		if err != nil {
+			return err
+		}
+		bucket := gen.Bucket(cacheBucket)
+		pBucket := gen.Bucket(provenanceBucket)
+		rBucket := gen.Bucket(redirectBucket)
+		sBucket := gen.Bucket(sourceBucket)
+		for canonicalID, secondaryIDs := range concordance {
+			canonicalUUID := s.uuids.uuidOf(canonicalID)
+			if only != nil && !s.anyOf(only, append([]string{canonicalID}, secondaryIDs...)) {
+				continue
+			}
+			cachedCanonical := bucket.Get([]byte(canonicalUUID))
+			if cachedCanonical == nil {
+				log.Warnf("Canonical author %v [%v] was not found in cache.", canonicalID, canonicalUUID)
+				continue
+			}
+			var canonical author
+			if err := json.Unmarshal(cachedCanonical, &canonical); err != nil {
+				return err
+			}
+			for _, secondaryID := range secondaryIDs {
+				secondaryUUID := s.uuids.uuidOf(secondaryID)
+				cachedSecondary := bucket.Get([]byte(secondaryUUID))
+				if cachedSecondary == nil {
+					log.Warnf("Author %v [%v] concorded with %v was not found in cache.", secondaryID, secondaryUUID, canonicalUUID)
+					continue
+				}
+				var secondary author
+				if err := json.Unmarshal(cachedSecondary, &secondary); err != nil {
+					return err
+				}
+				canonical = mergeSecondary(canonical, secondary)
+				if err := bucket.Delete([]byte(secondaryUUID)); err != nil {
+					return err
+				}
+				if err := pBucket.Delete([]byte(secondaryUUID)); err != nil {
+					return err
+				}
+				if err := mergeSources(sBucket, canonicalUUID, secondaryUUID); err != nil {
+					return err
+				}
+				if err := rBucket.Put([]byte(secondaryUUID), []byte(canonicalUUID)); err != nil {
+					return err
+				}
+			}
+			marshalledAuthor, err := json.Marshal(canonical)
+			if err != nil {
+				return err
+			}
+			if err := bucket.Put([]byte(canonicalUUID), marshalledAuthor); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// anyOf tells whether the UUID of any of the TME identifiers is in uuids
+func (s *authorServiceImpl) anyOf(uuids map[string]bool, tmeIdentifiers []string) bool {
+	for _, id := range tmeIdentifiers {
+		if uuids[s.uuids.uuidOf(id)] {
+			return true
+		}
+	}
+	return false
 }
 
-func addBerthaInformation(a author, b berthaAuthor) (author, error) {
-	berthaUUID := uuid.NewMD5(uuid.UUID{}, []byte(b.TmeIdentifier)).String()
+func overrideAuthor(gen StoreGeneration, o authorOverride) error {
+	bucket := gen.Bucket(cacheBucket)
+	cachedAuthor := bucket.Get([]byte(o.UUID))
+	if cachedAuthor == nil {
+		log.Warnf("Overridden author %v was not found in cache.", o.UUID)
+		return nil
+	}
+	var a author
+	if err := json.Unmarshal(cachedAuthor, &a); err != nil {
+		return err
+	}
+	a, err := applyOverride(a, o)
+	if err != nil {
+		return err
+	}
+	marshalledAuthor, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	if err := bucket.Put([]byte(a.UUID), marshalledAuthor); err != nil {
+		return err
+	}
+
+	pBucket := gen.Bucket(provenanceBucket)
+	var p authorProvenance
+	if cachedProvenance := pBucket.Get([]byte(a.UUID)); cachedProvenance != nil {
+		if err := json.Unmarshal(cachedProvenance, &p); err != nil {
+			return err
+		}
+	}
+	p.UUID = a.UUID
+	p.addOverrideProvenance(o)
+	return putProvenance(pBucket, p)
+}
+
+func putProvenance(bucket StoreBucket, p authorProvenance) error {
+	marshalledProvenance, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return bucket.Put([]byte(p.UUID), marshalledProvenance)
+}
+
+// findClosestMatches suggests the cached TME authors whose names are most similar to each unmatched curated author
+func findClosestMatches(bucket StoreBucket, unmatched []unmatchedCuratedAuthor) error {
+	if len(unmatched) == 0 {
+		return nil
+	}
+	orphans := make(map[string]bool)
+	for _, u := range unmatched {
+		orphans[u.UUID] = true
+	}
+	return bucket.ForEach(func(k, v []byte) error {
+		if orphans[string(k)] {
+			return nil
+		}
+		var a author
+		if err := json.Unmarshal(v, &a); err != nil {
+			return err
+		}
+		for i := range unmatched {
+			score := nameSimilarity(unmatched[i].Name, a.PrefLabel)
+			for _, alias := range a.Aliases {
+				if aliasScore := nameSimilarity(unmatched[i].Name, alias); aliasScore > score {
+					score = aliasScore
+				}
+			}
+			if score >= minMatchScore {
+				unmatched[i].ClosestMatches = addMatch(unmatched[i].ClosestMatches, authorMatch{UUID: a.UUID, PrefLabel: a.PrefLabel, Score: score})
+			}
+		}
+		return nil
+	})
+}
+
+func addMatch(matches []authorMatch, m authorMatch) []authorMatch {
+	matches = append(matches, m)
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	if len(matches) > maxClosestMatches {
+		matches = matches[:maxClosestMatches]
+	}
+	return matches
+}
+
+func addBerthaInformation(a author, b berthaAuthor, policy MergePolicy, uuids UUIDStrategy) (author, map[string]string, error) {
+	berthaUUID := uuids.uuidOf(b.TmeIdentifier)
 	if berthaUUID != a.UUID {
-		return a, errors.New("Bertha UUID doesn't match author UUID")
+		return a, nil, errors.New("Bertha UUID doesn't match author UUID")
 	}
-	plainDescription, err := html2text.FromString(b.Biography)
+	curated, err := berthaToAuthor(b, uuids)
 	if err != nil {
-		return a, err
+		return a, nil, err
 	}
-	a.Name = b.Name
-	a.PrefLabel = b.Name
-	a.EmailAddress = b.Email
-	a.TwitterHandle = b.TwitterHandle
-	a.FacebookProfile = b.FacebookProfile
-	a.LinkedinProfile = b.LinkedinProfile
-	a.Description = plainDescription
-	a.DescriptionXML = b.Biography
-	a.ImageURL = b.ImageURL
+	merged, sources := policy.merge(a, curated)
+	if source, ok := sources["name"]; ok {
+		merged = withParsedName(merged)
+		for _, f := range structuredNameFields {
+			sources[f] = source
+		}
+	}
+	if source, ok := sources["description"]; ok {
+		merged.ShortDescription = shortDescription(merged.Description)
+		sources["shortDescription"] = source
+	}
+	merged = withSocialProfiles(merged, curated.SocialProfiles)
+	if len(curated.SocialProfiles) > 0 {
+		sources["socialProfiles"] = sourceCurated
+	}
+	return merged, sources, nil
+}
+
+// sanitiseBiography replaces the biography of a curated author with its sanitised version and reports
+// what was changed. A biography which can't be sanitised is dropped.
+func sanitiseBiography(b berthaAuthor, berthaUUID string) (berthaAuthor, descriptionReport) {
+	report := descriptionReport{UUID: berthaUUID, TmeIdentifier: b.TmeIdentifier, Name: b.Name}
+	sanitised, changes, err := sanitiseDescription(b.Biography)
+	if err != nil {
+		log.Warnf("Dropping the biography of curated author %s [%s]: %v", b.Name, berthaUUID, err)
+		report.Error = err.Error()
+	}
+	report.Changes = changes
+	b.Biography = sanitised
+	return b, report
+}
 
-	return a, nil
+// checkImage validates and rewrites the image URL of a curated author, dropping it and reporting it when it's invalid
+func (s *authorServiceImpl) checkImage(b berthaAuthor, berthaUUID string) (berthaAuthor, *imageReport) {
+	imageURL, err := normaliseImageURL(b.ImageURL, s.imageOptions)
+	if err != nil {
+		log.Warnf("Dropping the image of curated author %s [%s]: %v", b.Name, berthaUUID, err)
+		report := imageReport{UUID: berthaUUID, TmeIdentifier: b.TmeIdentifier, Name: b.Name, ImageURL: b.ImageURL, Error: err.Error()}
+		b.ImageURL = ""
+		return b, &report
+	}
+	b.ImageURL = imageURL
+	return b, nil
 }
 
-func berthaToAuthor(a berthaAuthor) (author, error) {
-	berthaUUID := uuid.NewMD5(uuid.UUID{}, []byte(a.TmeIdentifier)).String()
-	plainDescription, err := html2text.FromString(a.Biography)
+func berthaToAuthor(a berthaAuthor, uuids UUIDStrategy) (author, error) {
+	berthaUUID := uuids.uuidOf(a.TmeIdentifier)
+	plainDescription, err := plainText(a.Biography)
 
 	if err != nil {
 		return author{}, err
@@ -443,10 +1355,15 @@ func berthaToAuthor(a berthaAuthor) (author, error) {
 		FacebookProfile:        a.FacebookProfile,
 		LinkedinProfile:        a.LinkedinProfile,
 		Description:            plainDescription,
+		ShortDescription:       shortDescription(plainDescription),
 		DescriptionXML:         a.Biography,
 		ImageURL:               a.ImageURL,
 		AlternativeIdentifiers: altIds,
 	}
+	if p.Name != "" || a.Aliases != "" {
+		p.Aliases = dedupeAliases(append(splitCuratedList(a.Aliases), a.Name))
+	}
 
-	return p, err
+	profiles, _ := curatedSocialProfiles(a, berthaUUID)
+	return withSocialProfiles(withParsedName(p), profiles), err
 }
@@ -14,6 +14,7 @@ import (
 	"bytes"
 
 	"github.com/Financial-Times/tme-reader/tmereader"
+	"github.com/pborman/uuid"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 )
@@ -211,7 +212,15 @@ func assertCount(t *testing.T, s AuthorService, expected int) {
 
 func createTestAuthorService(repo tmereader.Repository, cacheFileName string) AuthorService {
 	input := []berthaAuthor{}
-	return NewAuthorService(repo, "/base/url", "taxonomy_string", 1, cacheFileName, "/bertha/url", &mockClient{resp: input})
+	return createAuthorService([]Taxonomy{{Name: "taxonomy_string", Repository: repo}}, NewBoltAuthorStore(cacheFileName), &mockClient{resp: input}, ServiceOptions{})
+}
+
+// createAuthorService creates a service reading from the test TME and Bertha URLs
+func createAuthorService(taxonomies []Taxonomy, store AuthorStore, client httpClient, options ServiceOptions) AuthorService {
+	options.BaseURL = "/base/url"
+	options.MaxTmeRecords = 1
+	options.BerthaURL = "/bertha/url"
+	return NewAuthorService(taxonomies, store, client, options)
 }
 
 func getTempFile(t *testing.T) *os.File {
@@ -255,7 +264,7 @@ func (c *mockClient) Do(req *http.Request) (*http.Response, error) {
 		c.err = e
 	}
 	cb := ioutil.NopCloser(bytes.NewReader(b))
-	return &http.Response{Body: cb}, c.err
+	return &http.Response{StatusCode: http.StatusOK, Status: "200 OK", Body: cb}, c.err
 }
 
 type dummyRepo struct {
@@ -300,6 +309,12 @@ func (d *blockingRepo) GetTmeTermById(uuid string) (interface{}, error) {
 	return nil, nil
 }
 
+var terryProfiles = []socialProfile{
+	{Network: "twitter", Handle: "@terryorange", URL: "https://twitter.com/terryorange"},
+	{Network: "facebook", Handle: "terryorange", URL: "https://www.facebook.com/terryorange"},
+	{Network: "linkedin", Handle: "terryorange", URL: "https://www.linkedin.com/in/terryorange"},
+}
+
 func TestBerthaToAuthor(t *testing.T) {
 	testAuthor := berthaAuthor{
 		Name:            "Terry",
@@ -310,25 +325,29 @@ func TestBerthaToAuthor(t *testing.T) {
 		Biography:       "<h1>A test biography</h1>",
 		ImageURL:        "image-of-terry.jpg",
 		TmeIdentifier:   "1234567890",
+		Aliases:         "T. Orange; Terry O\nterry",
 	}
 	expectedAuthor := author{
-		UUID:            "e807f1fc-f82d-332f-9bb0-18ca6738a19f",
-		Name:            "Terry",
-		PrefLabel:       "Terry",
-		EmailAddress:    "terry@orange.com",
-		TwitterHandle:   "@terryorange",
-		FacebookProfile: "/terryorange",
-		LinkedinProfile: "terryorange",
-		Description:     "****************\nA test biography\n****************",
-		DescriptionXML:  "<h1>A test biography</h1>",
-		ImageURL:        "image-of-terry.jpg",
+		UUID:             "e807f1fc-f82d-332f-9bb0-18ca6738a19f",
+		Name:             "Terry",
+		PrefLabel:        "Terry",
+		EmailAddress:     "terry@orange.com",
+		TwitterHandle:    "@terryorange",
+		FacebookProfile:  "https://www.facebook.com/terryorange",
+		LinkedinProfile:  "https://www.linkedin.com/in/terryorange",
+		SocialProfiles:   terryProfiles,
+		Description:      "A test biography",
+		ShortDescription: "A test biography",
+		DescriptionXML:   "<h1>A test biography</h1>",
+		ImageURL:         "image-of-terry.jpg",
+		Aliases:          []string{"T. Orange", "Terry O", "Terry"},
 		AlternativeIdentifiers: alternativeIdentifiers{
 			UUIDs: []string{"e807f1fc-f82d-332f-9bb0-18ca6738a19f"},
 			TME:   []string{"1234567890"},
 		},
 	}
 
-	actualAuthor, err := berthaToAuthor(testAuthor)
+	actualAuthor, err := berthaToAuthor(testAuthor, UUIDStrategy{})
 	assert.Equal(t, expectedAuthor, actualAuthor)
 	assert.Nil(t, err)
 }
@@ -346,7 +365,7 @@ func TestBadAddBertha(t *testing.T) {
 	}
 	emptyAuthor := author{}
 
-	_, err := addBerthaInformation(emptyAuthor, testAuthor)
+	_, _, err := addBerthaInformation(emptyAuthor, testAuthor, DefaultMergePolicy(), UUIDStrategy{})
 	assert.EqualError(t, err, "Bertha UUID doesn't match author UUID")
 }
 
@@ -370,23 +389,26 @@ func TestGoodAddBertha(t *testing.T) {
 		},
 	}
 	expectedAuthor := author{
-		UUID:            "e807f1fc-f82d-332f-9bb0-18ca6738a19f",
-		Name:            "Terry",
-		PrefLabel:       "Terry",
-		EmailAddress:    "terry@orange.com",
-		TwitterHandle:   "@terryorange",
-		FacebookProfile: "/terryorange",
-		LinkedinProfile: "terryorange",
-		Description:     "****************\nA test biography\n****************",
-		DescriptionXML:  "<h1>A test biography</h1>",
-		ImageURL:        "image-of-terry.jpg",
+		UUID:             "e807f1fc-f82d-332f-9bb0-18ca6738a19f",
+		Name:             "Terry",
+		PrefLabel:        "Terry",
+		EmailAddress:     "terry@orange.com",
+		TwitterHandle:    "@terryorange",
+		FacebookProfile:  "https://www.facebook.com/terryorange",
+		LinkedinProfile:  "https://www.linkedin.com/in/terryorange",
+		SocialProfiles:   terryProfiles,
+		Description:      "A test biography",
+		ShortDescription: "A test biography",
+		DescriptionXML:   "<h1>A test biography</h1>",
+		ImageURL:         "image-of-terry.jpg",
+		Aliases:          []string{"Terry"},
 		AlternativeIdentifiers: alternativeIdentifiers{
 			UUIDs: []string{"e807f1fc-f82d-332f-9bb0-18ca6738a19f"},
 			TME:   []string{"1234567890"},
 		},
 	}
 
-	actualAuthor, err := addBerthaInformation(emptyAuthor, testAuthor)
+	actualAuthor, _, err := addBerthaInformation(emptyAuthor, testAuthor, DefaultMergePolicy(), UUIDStrategy{})
 	assert.Equal(t, expectedAuthor, actualAuthor)
 	assert.Nil(t, err)
 }
@@ -402,22 +424,25 @@ func TestLoadingCuratedAuthors(t *testing.T) {
 			FacebookProfile: "/terryorange",
 			LinkedinProfile: "terryorange",
 			Biography:       "<h1>A test biography</h1>",
-			ImageURL:        "image-of-terry.jpg",
+			ImageURL:        "https://im.ft-static.com/content/images/terry-orange.jpg",
 			TmeIdentifier:   "1234567890",
 		},
 	}
-	authorService := NewAuthorService(&dummyRepo{}, "/base/url", "taxonomy", 1, tmpfile.Name(), "/bertha/url", &mockClient{resp: input})
+	authorService := createAuthorService([]Taxonomy{{Name: "taxonomy", Repository: &dummyRepo{}}}, NewBoltAuthorStore(tmpfile.Name()), &mockClient{resp: input}, ServiceOptions{})
 	expectedAuthor := author{
-		UUID:            "e807f1fc-f82d-332f-9bb0-18ca6738a19f",
-		Name:            "Terry",
-		PrefLabel:       "Terry",
-		EmailAddress:    "terry@orange.com",
-		TwitterHandle:   "@terryorange",
-		FacebookProfile: "/terryorange",
-		LinkedinProfile: "terryorange",
-		Description:     "****************\nA test biography\n****************",
-		DescriptionXML:  "<h1>A test biography</h1>",
-		ImageURL:        "image-of-terry.jpg",
+		UUID:             "e807f1fc-f82d-332f-9bb0-18ca6738a19f",
+		Name:             "Terry",
+		PrefLabel:        "Terry",
+		EmailAddress:     "terry@orange.com",
+		TwitterHandle:    "@terryorange",
+		FacebookProfile:  "https://www.facebook.com/terryorange",
+		LinkedinProfile:  "https://www.linkedin.com/in/terryorange",
+		SocialProfiles:   terryProfiles,
+		Description:      "A test biography",
+		ShortDescription: "A test biography",
+		DescriptionXML:   "<h1>A test biography</h1>",
+		ImageURL:         "https://im.ft-static.com/content/images/terry-orange.jpg",
+		Aliases:          []string{"Terry"},
 		AlternativeIdentifiers: alternativeIdentifiers{
 			UUIDs: []string{"e807f1fc-f82d-332f-9bb0-18ca6738a19f"},
 			TME:   []string{"1234567890"},
@@ -432,3 +457,459 @@ func TestLoadingCuratedAuthors(t *testing.T) {
 	assert.EqualValues(t, expectedAuthor, actualOutput)
 	assert.Nil(t, err)
 }
+
+func TestLoadingCuratedAuthorsSanitisesBiographies(t *testing.T) {
+	tmpfile := getTempFile(t)
+	defer os.Remove(tmpfile.Name())
+	repo := dummyRepo{terms: []term{{CanonicalName: "Fred Black", RawID: "fred"}}}
+	fredIdentifier := buildTmeIdentifier("fred", "taxonomy_string")
+	input := []berthaAuthor{
+		{Name: "Fred Black", TmeIdentifier: fredIdentifier, Biography: `<p onclick="steal()">Fred <b>writes</b><script>alert("hi")</script></p>`},
+		{Name: "Terry Orange", TmeIdentifier: "1234567890", Biography: "<p>Terry writes</p>"},
+	}
+	service := createAuthorService([]Taxonomy{{Name: "taxonomy_string", Repository: &repo}}, NewBoltAuthorStore(tmpfile.Name()), &mockClient{resp: input}, ServiceOptions{})
+	defer service.Shutdown()
+	waitTillInit(t, service)
+	waitTillDataLoaded(t, service)
+
+	fred, found, err := service.getAuthorByUUID(uuid.NewMD5(uuid.UUID{}, []byte(fredIdentifier)).String())
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "<p>Fred <strong>writes</strong></p>", fred.DescriptionXML)
+
+	report := service.getReloadReport()
+	assert.Len(t, report.Descriptions, 1)
+	assert.Equal(t, fredIdentifier, report.Descriptions[0].TmeIdentifier)
+	assert.Equal(t, []string{"removed attribute onclick from <p>", "replaced <b> with <strong>", "removed <script> with its content"}, report.Descriptions[0].Changes)
+}
+
+func TestLoadingCuratedAuthorsChecksImages(t *testing.T) {
+	tmpfile := getTempFile(t)
+	defer os.Remove(tmpfile.Name())
+	repo := dummyRepo{terms: []term{{CanonicalName: "Fred Black", RawID: "fred"}}}
+	fredIdentifier := buildTmeIdentifier("fred", "taxonomy_string")
+	input := []berthaAuthor{
+		{Name: "Fred Black", TmeIdentifier: fredIdentifier, ImageURL: "fthead:fred-black"},
+		{Name: "Terry Orange", TmeIdentifier: "1234567890", ImageURL: "image-of-terry.jpg"},
+	}
+	service := createAuthorService([]Taxonomy{{Name: "taxonomy_string", Repository: &repo}}, NewBoltAuthorStore(tmpfile.Name()), &mockClient{resp: input}, ServiceOptions{Images: ImageOptions{Origami: true, Width: 150}})
+	defer service.Shutdown()
+	waitTillInit(t, service)
+	waitTillDataLoaded(t, service)
+
+	fred, found, err := service.getAuthorByUUID(uuid.NewMD5(uuid.UUID{}, []byte(fredIdentifier)).String())
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "https://www.ft.com/__origami/service/image/v2/images/raw/fthead:fred-black?source=next&width=150", fred.ImageURL)
+
+	terry, found, err := service.getAuthorByUUID("e807f1fc-f82d-332f-9bb0-18ca6738a19f")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Empty(t, terry.ImageURL)
+
+	report := service.getReloadReport()
+	assert.Equal(t, []imageReport{{UUID: "e807f1fc-f82d-332f-9bb0-18ca6738a19f", TmeIdentifier: "1234567890", Name: "Terry Orange",
+		ImageURL: "image-of-terry.jpg", Error: "image-of-terry.jpg is not an absolute URL"}}, report.Images)
+}
+
+func TestUnmatchedCuratedAuthors(t *testing.T) {
+	tmpfile := getTempFile(t)
+	defer os.Remove(tmpfile.Name())
+	repo := dummyRepo{terms: []term{{CanonicalName: "Terry Orange", RawID: "terry"}, {CanonicalName: "Fred Black", RawID: "fred"}}}
+	input := []berthaAuthor{
+		{Name: "Terri Orange", TmeIdentifier: "1234567890"},
+		{Name: "Fred Black", TmeIdentifier: buildTmeIdentifier("fred", "taxonomy_string")},
+	}
+	client := &mockClient{resp: input}
+	service := createAuthorService([]Taxonomy{{Name: "taxonomy_string", Repository: &repo}}, NewBoltAuthorStore(tmpfile.Name()), client, ServiceOptions{})
+	defer service.Shutdown()
+	waitTillInit(t, service)
+	waitTillDataLoaded(t, service)
+
+	unmatched := service.getUnmatchedCuratedAuthors()
+	assert.Len(t, unmatched, 1)
+	assert.Equal(t, "Terri Orange", unmatched[0].Name)
+	assert.Equal(t, "1234567890", unmatched[0].TmeIdentifier)
+	assert.Equal(t, "e807f1fc-f82d-332f-9bb0-18ca6738a19f", unmatched[0].UUID)
+	assert.Len(t, unmatched[0].ClosestMatches, 1)
+	assert.Equal(t, "Terry Orange", unmatched[0].ClosestMatches[0].PrefLabel)
+
+	client.resp = input[1:]
+	repo.count = 0
+	_, err := service.dryRunReload()
+	assert.NoError(t, err)
+	assert.Len(t, service.getUnmatchedCuratedAuthors(), 1, "A dry run should not change the unmatched authors being served")
+
+	repo.count = 0
+	assert.NoError(t, service.reloadDB())
+	assert.Empty(t, service.getUnmatchedCuratedAuthors())
+}
+
+func TestDuplicates(t *testing.T) {
+	tmpfile := getTempFile(t)
+	defer os.Remove(tmpfile.Name())
+	repo := dummyRepo{terms: []term{
+		{CanonicalName: "Martin Wolf", RawID: "wolf1"},
+		{CanonicalName: "Wolf, Martin", RawID: "wolf2"},
+		{CanonicalName: "Fred Black", RawID: "fred"},
+	}}
+	service := createTestAuthorService(&repo, tmpfile.Name())
+	defer service.Shutdown()
+	waitTillInit(t, service)
+	waitTillDataLoaded(t, service)
+
+	duplicates := service.getDuplicates()
+	assert.Len(t, duplicates, 1)
+	assert.Equal(t, 1.0, duplicates[0].Score)
+	assert.Equal(t, []string{"similarName"}, duplicates[0].MatchedOn)
+	assert.Len(t, duplicates[0].Authors, 2)
+	assert.Equal(t, "Martin Wolf", duplicates[0].Authors[0].PrefLabel)
+	assert.Equal(t, buildTmeIdentifier("wolf2", "taxonomy_string"), duplicates[0].Authors[1].TmeIdentifier)
+}
+
+func TestConcordance(t *testing.T) {
+	tmpfile := getTempFile(t)
+	defer os.Remove(tmpfile.Name())
+	repo := dummyRepo{terms: []term{
+		{CanonicalName: "Martin Wolf", RawID: "wolf1"},
+		{CanonicalName: "Wolf, Martin", RawID: "wolf2"},
+		{CanonicalName: "M Wolf", RawID: "wolf3"},
+		{CanonicalName: "Fred Black", RawID: "fred"},
+	}}
+	wolf1 := buildTmeIdentifier("wolf1", "taxonomy_string")
+	wolf2 := buildTmeIdentifier("wolf2", "taxonomy_string")
+	wolf3 := buildTmeIdentifier("wolf3", "taxonomy_string")
+	wolf1UUID := uuid.NewMD5(uuid.UUID{}, []byte(wolf1)).String()
+	wolf2UUID := uuid.NewMD5(uuid.UUID{}, []byte(wolf2)).String()
+	wolf3UUID := uuid.NewMD5(uuid.UUID{}, []byte(wolf3)).String()
+	service := createAuthorService([]Taxonomy{{Name: "taxonomy_string", Repository: &repo}}, NewBoltAuthorStore(tmpfile.Name()), &mockClient{resp: []berthaAuthor{}}, ServiceOptions{Concordance: Concordance{wolf1: {wolf2}}})
+	defer service.Shutdown()
+	waitTillInit(t, service)
+	waitTillDataLoaded(t, service)
+
+	assert.NoError(t, service.loadCuratedAuthors([]berthaAuthor{{Name: "Martin Wolf", TmeIdentifier: wolf1, SecondaryTmeIdentifiers: wolf3}}))
+	assert.NoError(t, service.(*authorServiceImpl).applyConcordance(nil))
+
+	canonical, found, err := service.getAuthorByUUID(wolf1UUID)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []string{wolf1, wolf2, wolf3}, canonical.AlternativeIdentifiers.TME)
+	assert.Equal(t, []string{wolf1UUID, wolf2UUID, wolf3UUID}, canonical.AlternativeIdentifiers.UUIDs)
+	assert.Equal(t, []string{"Martin Wolf", "Wolf, Martin", "M Wolf"}, canonical.Aliases)
+
+	for _, secondaryUUID := range []string{wolf2UUID, wolf3UUID} {
+		_, found, err = service.getAuthorByUUID(secondaryUUID)
+		assert.NoError(t, err)
+		assert.False(t, found)
+		canonicalUUID, merged, err := service.getCanonicalUUID(secondaryUUID)
+		assert.NoError(t, err)
+		assert.True(t, merged)
+		assert.Equal(t, wolf1UUID, canonicalUUID)
+	}
+	_, merged, err := service.getCanonicalUUID(wolf1UUID)
+	assert.NoError(t, err)
+	assert.False(t, merged)
+
+	count, err := service.getCount()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestAuthorProvenance(t *testing.T) {
+	tmpfile := getTempFile(t)
+	defer os.Remove(tmpfile.Name())
+	repo := dummyRepo{terms: []term{{CanonicalName: "Fred Black", RawID: "fred"}}}
+	service := createTestAuthorService(&repo, tmpfile.Name())
+	defer service.Shutdown()
+	waitTillInit(t, service)
+	waitTillDataLoaded(t, service)
+
+	fredIdentifier := buildTmeIdentifier("fred", "taxonomy_string")
+	fredUUID := uuid.NewMD5(uuid.UUID{}, []byte(fredIdentifier)).String()
+	p, found, err := service.getAuthorProvenance(fredUUID)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "TME", p.Fields["name"].Source)
+	assert.Equal(t, fredIdentifier, p.Fields["name"].SourceID)
+
+	input := []berthaAuthor{
+		{Name: "Terry", TmeIdentifier: "1234567890"},
+		{Name: "Fred Black", Email: "fred@black.com", TmeIdentifier: fredIdentifier},
+	}
+	assert.NoError(t, service.loadCuratedAuthors(input))
+
+	p, found, err = service.getAuthorProvenance(fredUUID)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, fredUUID, p.UUID)
+	assert.Equal(t, fieldProvenance{Source: "TME+Bertha", SourceID: fredIdentifier, SourceRow: 2, LoadedAt: p.Fields["aliases"].LoadedAt}, p.Fields["aliases"])
+	assert.Equal(t, "Bertha", p.Fields["emailAddress"].Source)
+	assert.Equal(t, fredIdentifier, p.Fields["emailAddress"].SourceID)
+	assert.Equal(t, 2, p.Fields["emailAddress"].SourceRow)
+	assert.False(t, p.Fields["emailAddress"].LoadedAt.IsZero())
+
+	p, found, err = service.getAuthorProvenance("e807f1fc-f82d-332f-9bb0-18ca6738a19f")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "Bertha", p.Fields["uuid"].Source)
+	assert.Equal(t, 1, p.Fields["uuid"].SourceRow)
+
+	_, found, err = service.getAuthorProvenance("xxxxxxxx-bb56-363d-80c1-f2d957ef58cf")
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestOverrides(t *testing.T) {
+	tmpfile := getTempFile(t)
+	defer os.Remove(tmpfile.Name())
+	repo := dummyRepo{terms: []term{{CanonicalName: "Fred Black", RawID: "fred", Raw: "<name>Fred Black</name><id>fred</id>"}}}
+	service := createTestAuthorService(&repo, tmpfile.Name())
+	defer service.Shutdown()
+	waitTillInit(t, service)
+	waitTillDataLoaded(t, service)
+
+	fredUUID := uuid.NewMD5(uuid.UUID{}, []byte(buildTmeIdentifier("fred", "taxonomy_string"))).String()
+	bio := "<p>Fred Black writes about markets</p>"
+	o, found, err := service.saveOverride(fredUUID, overrideRequest{Fields: map[string]*string{"descriptionXML": &bio}, User: "jane.doe", Reason: "Typo in biography"}, true)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, map[string]string{"descriptionXML": bio}, o.Fields)
+
+	name := "Frederick Black"
+	_, found, err = service.saveOverride(fredUUID, overrideRequest{Fields: map[string]*string{"name": &name}, User: "john.doe", Reason: "Full name"}, false)
+	assert.NoError(t, err)
+	assert.True(t, found)
+
+	a, _, err := service.getAuthorByUUID(fredUUID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Frederick Black", a.Name)
+	assert.Equal(t, "Fred Black", a.PrefLabel)
+	assert.Equal(t, bio, a.DescriptionXML)
+	assert.Equal(t, "Fred Black writes about markets", a.Description)
+	assert.Equal(t, "Fred Black writes about markets", a.ShortDescription)
+
+	p, _, err := service.getAuthorProvenance(fredUUID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Override", p.Fields["description"].Source)
+	assert.Equal(t, "Override", p.Fields["shortDescription"].Source)
+	assert.Equal(t, "john.doe", p.Fields["name"].SourceID)
+	assert.Equal(t, "TME", p.Fields["prefLabel"].Source)
+
+	_, found, err = service.saveOverride("xxxxxxxx-bb56-363d-80c1-f2d957ef58cf", overrideRequest{Fields: map[string]*string{"name": &name}, User: "john.doe", Reason: "Full name"}, false)
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	repo.count = 0
+	assert.NoError(t, service.reloadDB())
+	waitTillDataLoaded(t, service)
+	a, _, err = service.getAuthorByUUID(fredUUID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Frederick Black", a.Name, "Overrides should survive a reload")
+	assert.Equal(t, bio, a.DescriptionXML, "Overrides should survive a reload")
+
+	overrides, err := service.getOverrides()
+	assert.NoError(t, err)
+	assert.Len(t, overrides, 1)
+	assert.Equal(t, "john.doe", overrides[0].User)
+	assert.Equal(t, "Full name", overrides[0].Reason)
+	assert.Equal(t, map[string]string{"descriptionXML": bio, "name": name}, overrides[0].Fields)
+
+	found, err = service.deleteOverride(fredUUID)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	found, err = service.deleteOverride(fredUUID)
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	a, _, err = service.getAuthorByUUID(fredUUID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Fred Black", a.Name, "The author should be restored straight away")
+	assert.Equal(t, "Fred", a.GivenName)
+	assert.Empty(t, a.DescriptionXML)
+	assert.Empty(t, a.Description)
+	assert.Empty(t, a.ShortDescription)
+	p, _, err = service.getAuthorProvenance(fredUUID)
+	assert.NoError(t, err)
+	assert.Equal(t, "TME", p.Fields["name"].Source)
+	assert.Equal(t, "TME", p.Fields["givenName"].Source)
+	_, overridden := p.Fields["description"]
+	assert.False(t, overridden)
+
+	repo.count = 0
+	assert.NoError(t, service.reloadDB())
+	waitTillDataLoaded(t, service)
+	a, _, err = service.getAuthorByUUID(fredUUID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Fred Black", a.Name)
+	assert.Empty(t, a.DescriptionXML)
+}
+
+func TestRemovedOverrideFieldsAreRestoredFromTheCuratedAuthor(t *testing.T) {
+	tmpfile := getTempFile(t)
+	defer os.Remove(tmpfile.Name())
+	fredIdentifier := buildTmeIdentifier("fred", "taxonomy_string")
+	repo := dummyRepo{terms: []term{{CanonicalName: "Fred Black", RawID: "fred", Raw: "<name>Fred Black</name><id>fred</id>"}}}
+	input := []berthaAuthor{{Name: "Fred Black", TmeIdentifier: fredIdentifier, Biography: "<p>Fred Black writes about markets</p>", TwitterHandle: "@fredblack"}}
+	service := createAuthorService([]Taxonomy{{Name: "taxonomy_string", Repository: &repo}}, NewBoltAuthorStore(tmpfile.Name()), &mockClient{resp: input}, ServiceOptions{})
+	defer service.Shutdown()
+	waitTillInit(t, service)
+	waitTillDataLoaded(t, service)
+
+	fredUUID := UUIDStrategy{}.uuidOf(fredIdentifier)
+	bio := "<p>Fred Black writes about bonds</p>"
+	twitter := "@fred"
+	_, found, err := service.saveOverride(fredUUID, overrideRequest{Fields: map[string]*string{"descriptionXML": &bio, "twitterHandle": &twitter}, User: "jane.doe", Reason: "New beat"}, true)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	a, _, err := service.getAuthorByUUID(fredUUID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Fred Black writes about bonds", a.ShortDescription)
+	assert.Equal(t, "@fred", a.TwitterHandle)
+
+	_, found, err = service.saveOverride(fredUUID, overrideRequest{Fields: map[string]*string{"descriptionXML": nil}, User: "jane.doe", Reason: "Back to markets"}, false)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	a, _, err = service.getAuthorByUUID(fredUUID)
+	assert.NoError(t, err)
+	assert.Equal(t, "<p>Fred Black writes about markets</p>", a.DescriptionXML, "The curated biography should be restored straight away")
+	assert.Equal(t, "Fred Black writes about markets", a.Description)
+	assert.Equal(t, "Fred Black writes about markets", a.ShortDescription)
+	assert.Equal(t, "@fred", a.TwitterHandle, "The fields still overridden should be kept")
+	p, _, err := service.getAuthorProvenance(fredUUID)
+	assert.NoError(t, err)
+	assert.Equal(t, fieldProvenance{Source: "Bertha", SourceID: fredIdentifier, SourceRow: 1, LoadedAt: p.Fields["descriptionXML"].LoadedAt}, p.Fields["descriptionXML"])
+	assert.Equal(t, "Bertha", p.Fields["shortDescription"].Source)
+	assert.Equal(t, "Override", p.Fields["twitterHandle"].Source)
+
+	found, err = service.deleteOverride(fredUUID)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	a, _, err = service.getAuthorByUUID(fredUUID)
+	assert.NoError(t, err)
+	assert.Equal(t, "@fredblack", a.TwitterHandle)
+	if assert.Len(t, a.SocialProfiles, 1) {
+		assert.Equal(t, "@fredblack", a.SocialProfiles[0].Handle)
+	}
+}
+
+func TestUUIDStrategy(t *testing.T) {
+	tmpfile := getTempFile(t)
+	defer os.Remove(tmpfile.Name())
+	repo := dummyRepo{terms: []term{{CanonicalName: "Fred Black", RawID: "fred"}, {CanonicalName: "Martin Wolf", RawID: "wolf"}}}
+	wolfIdentifier := buildTmeIdentifier("wolf", "taxonomy_string")
+	uuids := UUIDStrategy{V5Taxonomies: []string{"taxonomy_string"}, Legacy: map[string]string{wolfIdentifier: "0f07d468-fc37-3c44-bf19-a81f2aae9f36"}}
+	service := createAuthorService([]Taxonomy{{Name: "taxonomy_string", Repository: &repo}}, NewBoltAuthorStore(tmpfile.Name()), &mockClient{resp: []berthaAuthor{}}, ServiceOptions{UUIDs: uuids})
+	defer service.Shutdown()
+	waitTillInit(t, service)
+	waitTillDataLoaded(t, service)
+
+	fredIdentifier := buildTmeIdentifier("fred", "taxonomy_string")
+	fred, found, err := service.getAuthorByUUID(uuid.NewSHA1(DefaultUUIDNamespace, []byte(fredIdentifier)).String())
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "Fred Black", fred.Name)
+
+	wolf, found, err := service.getAuthorByUUID("0f07d468-fc37-3c44-bf19-a81f2aae9f36")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []string{"0f07d468-fc37-3c44-bf19-a81f2aae9f36"}, wolf.AlternativeIdentifiers.UUIDs)
+
+	mismatches, err := service.checkUUIDs()
+	assert.NoError(t, err)
+	assert.Empty(t, mismatches)
+}
+
+func TestMultipleTaxonomies(t *testing.T) {
+	tmpfile := getTempFile(t)
+	defer os.Remove(tmpfile.Name())
+	authorsRepo := dummyRepo{terms: []term{{CanonicalName: "Martin Wolf", RawID: "wolf"}}}
+	columnistsRepo := dummyRepo{terms: []term{
+		{CanonicalName: "Martin Wolf", RawID: "wolf", Aliases: aliases{Alias: []alias{{Name: "Wolf"}}}},
+		{CanonicalName: "Lucy Kellaway", RawID: "kellaway"},
+	}}
+	service := createAuthorService([]Taxonomy{{Name: "Authors", Repository: &authorsRepo}, {Name: "Columnists", Repository: &columnistsRepo}}, NewBoltAuthorStore(tmpfile.Name()), &mockClient{resp: []berthaAuthor{}}, ServiceOptions{})
+	defer service.Shutdown()
+	waitTillInit(t, service)
+	waitTillDataLoaded(t, service)
+	assertCount(t, service, 2)
+
+	wolfAuthors := buildTmeIdentifier("wolf", "Authors")
+	wolfColumnists := buildTmeIdentifier("wolf", "Columnists")
+	wolfUUID := uuid.NewMD5(uuid.UUID{}, []byte(wolfAuthors)).String()
+	wolf, found, err := service.getAuthorByUUID(wolfUUID)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []string{"Authors", "Columnists"}, wolf.Taxonomies)
+	assert.Equal(t, []string{wolfAuthors, wolfColumnists}, wolf.AlternativeIdentifiers.TME)
+	assert.Equal(t, []string{"Martin Wolf", "Wolf"}, wolf.Aliases)
+
+	canonicalUUID, found, err := service.getCanonicalUUID(uuid.NewMD5(uuid.UUID{}, []byte(wolfColumnists)).String())
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, wolfUUID, canonicalUUID)
+
+	kellaway, found, err := service.getAuthorByUUID(uuid.NewMD5(uuid.UUID{}, []byte(buildTmeIdentifier("kellaway", "Columnists"))).String())
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, []string{"Columnists"}, kellaway.Taxonomies)
+}
+
+func TestAuthorSource(t *testing.T) {
+	tmpfile := getTempFile(t)
+	defer os.Remove(tmpfile.Name())
+	repo := dummyRepo{terms: []term{{CanonicalName: "Fred Black", RawID: "fred", Raw: "<name>Fred Black</name><id>fred</id>"}}}
+	service := createTestAuthorService(&repo, tmpfile.Name())
+	defer service.Shutdown()
+	waitTillInit(t, service)
+	waitTillDataLoaded(t, service)
+
+	fredIdentifier := buildTmeIdentifier("fred", "taxonomy_string")
+	fredUUID := uuid.NewMD5(uuid.UUID{}, []byte(fredIdentifier)).String()
+	row := json.RawMessage(`{"name":"Fred Black","tmeidentifier":"` + fredIdentifier + `"}`)
+	assert.NoError(t, service.loadCuratedAuthors([]berthaAuthor{{Name: "Fred Black", TmeIdentifier: fredIdentifier, Raw: row}}))
+
+	src, found, err := service.getAuthorSource(fredUUID)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, authorSource{UUID: fredUUID, TME: []tmeSource{{TmeIdentifier: fredIdentifier, Taxonomy: "taxonomy_string", XML: "<term><name>Fred Black</name><id>fred</id></term>"}},
+		Bertha: row}, src)
+
+	_, found, err = service.getAuthorSource("0f07d468-fc37-3c44-bf19-a81f2aae9f36")
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestGetBerthaAuthorsKeepsRawRows(t *testing.T) {
+	tmpfile := getTempFile(t)
+	defer os.Remove(tmpfile.Name())
+	service := createTestAuthorService(&dummyRepo{}, tmpfile.Name())
+	defer service.Shutdown()
+	waitTillInit(t, service)
+	waitTillReloadFinished(t, service)
+
+	impl := service.(*authorServiceImpl)
+	impl.httpClient = &mockClient{resp: []berthaAuthor{{Name: "Terry Orange", TmeIdentifier: "1234567890"}}}
+	bAuthors, err := impl.getBerthaAuthors("/bertha/url")
+	assert.NoError(t, err)
+	assert.Len(t, bAuthors, 1)
+	assert.Equal(t, "Terry Orange", bAuthors[0].Name)
+	assert.Contains(t, string(bAuthors[0].Raw), `"name":"Terry Orange"`)
+}
+
+func TestGetBerthaAuthorsFails(t *testing.T) {
+	tmpfile := getTempFile(t)
+	defer os.Remove(tmpfile.Name())
+	service := createTestAuthorService(&dummyRepo{}, tmpfile.Name())
+	defer service.Shutdown()
+	waitTillInit(t, service)
+	waitTillReloadFinished(t, service)
+
+	impl := service.(*authorServiceImpl)
+	impl.httpClient = &ReplayClient{responses: map[string]recordedResponse{}}
+	_, err := impl.getBerthaAuthors("/bertha/url")
+	assert.EqualError(t, err, "No recorded response for GET /bertha/url")
+
+	impl.httpClient = &pageClient{}
+	_, err = impl.getBerthaAuthors("/bertha/url")
+	assert.EqualError(t, err, "Fetching the curated authors from /bertha/url answered 404 Not Found")
+}
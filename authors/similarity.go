@@ -0,0 +1,76 @@
+package authors
+
+import (
+	"sort"
+	"strings"
+)
+
+// normaliseName lower-cases a name and collapses its whitespace so that
+// trivially different spellings compare as equal
+func normaliseName(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+}
+
+// nameSimilarity returns a score between 0 and 1 describing how alike two
+// names are. Token order is ignored so "Wolf, Martin" still matches "Martin Wolf".
+func nameSimilarity(a, b string) float64 {
+	na, nb := normaliseName(a), normaliseName(b)
+	if na == "" || nb == "" {
+		return 0
+	}
+	score := levenshteinRatio(na, nb)
+	if sorted := levenshteinRatio(sortTokens(na), sortTokens(nb)); sorted > score {
+		score = sorted
+	}
+	return score
+}
+
+func sortTokens(name string) string {
+	tokens := strings.FieldsFunc(name, func(r rune) bool {
+		return r == ' ' || r == ','
+	})
+	sort.Strings(tokens)
+	return strings.Join(tokens, " ")
+}
+
+func levenshteinRatio(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
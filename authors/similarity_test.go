@@ -0,0 +1,32 @@
+package authors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNameSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected float64
+	}{
+		{"Martin Wolf", "Martin Wolf", 1},
+		{"Martin  Wolf ", "martin wolf", 1},
+		{"Wolf, Martin", "Martin Wolf", 1},
+		{"Martin Wolf", "", 0},
+		{"abcd", "wxyz", 0},
+		{"Jon Smith", "John Smith", 0.9},
+	}
+	for _, test := range tests {
+		assert.InDelta(t, test.expected, nameSimilarity(test.a, test.b), 0.001, "%q vs %q", test.a, test.b)
+	}
+}
+
+func TestAddMatchKeepsBestMatches(t *testing.T) {
+	var matches []authorMatch
+	for i, score := range []float64{0.7, 0.9, 0.65, 0.8} {
+		matches = addMatch(matches, authorMatch{UUID: string(rune('a' + i)), Score: score})
+	}
+	assert.Equal(t, []authorMatch{{UUID: "b", Score: 0.9}, {UUID: "d", Score: 0.8}, {UUID: "a", Score: 0.7}}, matches)
+}
@@ -0,0 +1,311 @@
+package authors
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	snapshotVersion  = 1
+	snapshotMetadata = "snapshot.json"
+)
+
+// snapshot is the metadata of a snapshot of a generation of authors. Each bucket of the generation is in
+// a file of the snapshot, one JSON value per line.
+type snapshot struct {
+	Version    int            `json:"version"`
+	CreatedAt  time.Time      `json:"createdAt"`
+	Generation generation     `json:"generation"`
+	Files      []snapshotFile `json:"files"`
+}
+
+type snapshotFile struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+type snapshotRedirect struct {
+	UUID          string `json:"uuid"`
+	CanonicalUUID string `json:"canonicalUuid"`
+}
+
+func snapshotFileName(bucket string) string {
+	return bucket + ".ndjson"
+}
+
+// snapshotLines calls write with each line of the snapshot file of a bucket
+func snapshotLines(gen StoreGeneration, bucket string, write func(line []byte) error) error {
+	return gen.Bucket(bucket).ForEach(func(k, v []byte) error {
+		if bucket == redirectBucket {
+			var err error
+			if v, err = json.Marshal(snapshotRedirect{UUID: string(k), CanonicalUUID: string(v)}); err != nil {
+				return err
+			}
+		}
+		return write(append(append([]byte{}, v...), '\n'))
+	})
+}
+
+// getSnapshot streams a gzipped tar snapshot of the active generation, and gives the ID of the generation,
+// which is empty when no generation is active
+func (s *authorServiceImpl) getSnapshot() (io.PipeReader, string, error) {
+	s.RLock()
+	if s.active == "" {
+		s.RUnlock()
+		return io.PipeReader{}, "", nil
+	}
+	id := s.active
+	pv, pw := io.Pipe()
+	go func() {
+		defer s.RUnlock()
+		pw.CloseWithError(s.store.View(func(tx StoreTx) error {
+			return writeSnapshot(tx, id, pw)
+		}))
+	}()
+	return *pv, id, nil
+}
+
+func writeSnapshot(tx StoreTx, id string, w io.Writer) error {
+	g, err := readGeneration(tx, id)
+	if err != nil {
+		return err
+	}
+	gen, err := generationOf(tx, id)
+	if err != nil {
+		return err
+	}
+	meta := snapshot{Version: snapshotVersion, CreatedAt: time.Now(), Generation: g}
+	for _, bucket := range generationBuckets {
+		f := snapshotFile{Name: snapshotFileName(bucket)}
+		h := sha256.New()
+		if err := snapshotLines(gen, bucket, func(line []byte) error {
+			f.Size += int64(len(line))
+			_, err := h.Write(line)
+			return err
+		}); err != nil {
+			return err
+		}
+		f.SHA256 = hex.EncodeToString(h.Sum(nil))
+		meta.Files = append(meta.Files, f)
+	}
+	marshalledMeta, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	if err := writeArchiveFile(tw, snapshotMetadata, marshalledMeta); err != nil {
+		return err
+	}
+	for i, bucket := range generationBuckets {
+		if err := tw.WriteHeader(&tar.Header{Name: meta.Files[i].Name, Mode: 0644, Size: meta.Files[i].Size, ModTime: meta.CreatedAt}); err != nil {
+			return err
+		}
+		if err := snapshotLines(gen, bucket, func(line []byte) error {
+			_, err := tw.Write(line)
+			return err
+		}); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// readSnapshot reads the metadata and files of a snapshot, checking their version and checksums
+func readSnapshot(r io.Reader) (snapshot, map[string][]byte, error) {
+	var meta snapshot
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return meta, nil, fmt.Errorf("Snapshot is not gzipped: %v", err)
+	}
+	tr := tar.NewReader(gz)
+	files := make(map[string][]byte)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return meta, nil, fmt.Errorf("Snapshot is not a valid tar: %v", err)
+		}
+		contents, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return meta, nil, err
+		}
+		files[header.Name] = contents
+	}
+
+	marshalledMeta, ok := files[snapshotMetadata]
+	if !ok {
+		return meta, nil, fmt.Errorf("Snapshot has no %v", snapshotMetadata)
+	}
+	if err := json.Unmarshal(marshalledMeta, &meta); err != nil {
+		return meta, nil, fmt.Errorf("Metadata of the snapshot is not valid JSON: %v", err)
+	}
+	if meta.Version != snapshotVersion {
+		return meta, nil, fmt.Errorf("Snapshot version %d is not supported, only version %d is", meta.Version, snapshotVersion)
+	}
+	listed := make(map[string]bool)
+	for _, f := range meta.Files {
+		contents, ok := files[f.Name]
+		if !ok {
+			return meta, nil, fmt.Errorf("Snapshot has no %v", f.Name)
+		}
+		if sum := sha256.Sum256(contents); hex.EncodeToString(sum[:]) != f.SHA256 {
+			return meta, nil, fmt.Errorf("Checksum of %v doesn't match %v", f.Name, f.SHA256)
+		}
+		listed[f.Name] = true
+	}
+	for _, bucket := range generationBuckets {
+		if !listed[snapshotFileName(bucket)] {
+			return meta, nil, fmt.Errorf("Metadata of the snapshot has no checksum of %v", snapshotFileName(bucket))
+		}
+	}
+	return meta, files, nil
+}
+
+// openSnapshot opens a snapshot from a file or an http(s) URL
+func (s *authorServiceImpl) openSnapshot(source string) (io.ReadCloser, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		return os.Open(source)
+	}
+	req, err := http.NewRequest("GET", source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("Fetching snapshot %v answered %v", source, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// importSnapshot loads a snapshot into a new generation and activates it, unless it has no authors or the reload
+// guard refuses it
+func (s *authorServiceImpl) importSnapshot(source string) error {
+	s.reloading.Lock()
+	defer s.reloading.Unlock()
+	r, err := s.openSnapshot(source)
+	if err != nil {
+		return err
+	}
+	meta, files, err := readSnapshot(r)
+	r.Close()
+	if err != nil {
+		return err
+	}
+	if err := s.openDB(); err != nil {
+		s.setInitialised(false)
+		return err
+	}
+	if err := s.newGeneration(time.Now(), false); err != nil {
+		return err
+	}
+	if err := s.importGeneration(source, meta, files); err != nil {
+		s.abandonGeneration()
+		return err
+	}
+	return nil
+}
+
+// importGeneration puts the files of a snapshot into the generation being built and activates it
+func (s *authorServiceImpl) importGeneration(source string, meta snapshot, files map[string][]byte) error {
+	s.Lock()
+	defer s.Unlock()
+	var g generation
+	err := s.store.Update(func(tx StoreTx) error {
+		gen, err := generationOf(tx, s.building)
+		if err != nil {
+			return err
+		}
+		counts := make(map[string]int)
+		for _, bucket := range generationBuckets {
+			if counts[bucket], err = importLines(gen.Bucket(bucket), bucket, files[snapshotFileName(bucket)]); err != nil {
+				return fmt.Errorf("Snapshot file %v is not valid: %v", snapshotFileName(bucket), err)
+			}
+		}
+		if g, err = readGeneration(tx, s.building); err != nil {
+			return err
+		}
+		finishedAt := time.Now()
+		g.FinishedAt = &finishedAt
+		g.Count = counts[cacheBucket]
+		g.CuratedMatches = meta.Generation.CuratedMatches
+		g.SnapshotOf = meta.Generation.ID
+		if g.Count == 0 {
+			return fmt.Errorf("Snapshot %v has no authors", source)
+		}
+		active, err := s.readActive(tx)
+		if err != nil {
+			return err
+		}
+		if reasons := s.guard.check(active, g); len(reasons) > 0 {
+			return fmt.Errorf("Snapshot %v was not imported: %v", source, strings.Join(reasons, ", "))
+		}
+		g, err = activate(tx, g, s.active, s.keptGenerations)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	log.Infof("Imported snapshot %v of generation %v.", source, meta.Generation.ID)
+	s.activated(g)
+	return nil
+}
+
+// importLines puts the lines of the snapshot file of a bucket into the bucket, keyed by their UUID, and
+// counts them
+func importLines(b StoreBucket, bucket string, contents []byte) (int, error) {
+	count := 0
+	for _, line := range bytes.Split(contents, []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		count++
+		if bucket == redirectBucket {
+			var r snapshotRedirect
+			if err := json.Unmarshal(line, &r); err != nil {
+				return count, err
+			}
+			if err := b.Put([]byte(r.UUID), []byte(r.CanonicalUUID)); err != nil {
+				return count, err
+			}
+			continue
+		}
+		var keyed struct {
+			UUID string `json:"uuid"`
+		}
+		if err := json.Unmarshal(line, &keyed); err != nil {
+			return count, err
+		}
+		if keyed.UUID == "" {
+			return count, fmt.Errorf("%v has no UUID", string(line))
+		}
+		if err := b.Put([]byte(keyed.UUID), line); err != nil {
+			return count, err
+		}
+	}
+	return count, nil
+}
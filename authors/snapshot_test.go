@@ -0,0 +1,130 @@
+package authors
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeedSnapshot(t *testing.T) {
+	tmpfile := getTempFile(t)
+	defer os.Remove(tmpfile.Name())
+	repo := dummyRepo{terms: []term{{CanonicalName: "Bob", RawID: "bob"}, {CanonicalName: "Fred", RawID: "fred"}}}
+	curated := []berthaAuthor{{Name: "Bob", TmeIdentifier: buildTmeIdentifier("bob", "taxonomy_string")}}
+	service := createAuthorService([]Taxonomy{{Name: "taxonomy_string", Repository: &repo}}, NewBoltAuthorStore(tmpfile.Name()), &mockClient{resp: curated}, ServiceOptions{})
+	defer service.Shutdown()
+	waitTillInit(t, service)
+	waitTillDataLoaded(t, service)
+
+	snapshotFile, err := os.Create(getTempFile(t).Name())
+	assert.NoError(t, err)
+	defer os.Remove(snapshotFile.Name())
+	pv, id, err := service.getSnapshot()
+	assert.NoError(t, err)
+	assert.Equal(t, "00000001", id)
+	_, err = io.Copy(snapshotFile, &pv)
+	assert.NoError(t, err)
+	snapshotFile.Close()
+
+	emptyRepo := dummyRepo{}
+	seeded := createAuthorService([]Taxonomy{{Name: "taxonomy_string", Repository: &emptyRepo}}, NewMemoryAuthorStore(), &mockClient{resp: []berthaAuthor{}}, ServiceOptions{Guard: ReloadGuard{MinCuratedMatches: 1}, Startup: StartupOptions{SeedSnapshot: snapshotFile.Name()}})
+	defer seeded.Shutdown()
+	waitTillInit(t, seeded)
+	waitTillReloadFinished(t, seeded)
+	assert.True(t, seeded.isDataLoaded(), "The snapshot should be served while the reload is blocked")
+	assertCount(t, seeded, 2)
+	_, blocked := seeded.getBlockedGeneration()
+	assert.True(t, blocked)
+
+	generations, err := seeded.getGenerations()
+	assert.NoError(t, err)
+	assert.Equal(t, "active", generations[1].Status)
+	assert.Equal(t, "00000001", generations[1].SnapshotOf)
+	assert.Equal(t, 2, generations[1].Count)
+}
+
+func TestReadSnapshotChecksFiles(t *testing.T) {
+	_, _, err := readSnapshot(bytes.NewReader(testSnapshot(t, `{"version":1,"files":[{"name":"author.ndjson","size":2,"sha256":"00"}]}`, "{}")))
+	assert.EqualError(t, err, "Checksum of author.ndjson doesn't match 00")
+
+	_, _, err = readSnapshot(bytes.NewReader(testSnapshot(t, `{"version":1,"files":[{"name":"author.ndjson","size":2,"sha256":"44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a"}]}`, "{}")))
+	assert.EqualError(t, err, "Metadata of the snapshot has no checksum of provenance.ndjson")
+
+	_, _, err = readSnapshot(bytes.NewReader(testSnapshot(t, `{"version":2}`, "")))
+	assert.EqualError(t, err, "Snapshot version 2 is not supported, only version 1 is")
+
+	_, _, err = readSnapshot(bytes.NewReader([]byte("not a snapshot")))
+	assert.Error(t, err)
+}
+
+func testSnapshot(t *testing.T, meta string, authors string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	assert.NoError(t, writeArchiveFile(tw, snapshotMetadata, []byte(meta)))
+	assert.NoError(t, writeArchiveFile(tw, "author.ndjson", []byte(authors)))
+	assert.NoError(t, tw.Close())
+	assert.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func TestImportSnapshotRefusals(t *testing.T) {
+	tmpfile := getTempFile(t)
+	defer os.Remove(tmpfile.Name())
+	repo := dummyRepo{terms: []term{{CanonicalName: "Bob", RawID: "bob"}}}
+	service := createTestAuthorService(&repo, tmpfile.Name())
+	defer service.Shutdown()
+	waitTillInit(t, service)
+	waitTillDataLoaded(t, service)
+	impl := service.(*authorServiceImpl)
+
+	var files []string
+	for _, bucket := range generationBuckets {
+		files = append(files, `{"name":"`+snapshotFileName(bucket)+`","size":0,"sha256":"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"}`)
+	}
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	assert.NoError(t, writeArchiveFile(tw, snapshotMetadata, []byte(`{"version":1,"files":[`+strings.Join(files, ",")+`]}`)))
+	for _, bucket := range generationBuckets {
+		assert.NoError(t, writeArchiveFile(tw, snapshotFileName(bucket), nil))
+	}
+	assert.NoError(t, tw.Close())
+	assert.NoError(t, gz.Close())
+	emptyFile := getTempFile(t).Name()
+	defer os.Remove(emptyFile)
+	assert.NoError(t, ioutil.WriteFile(emptyFile, buf.Bytes(), 0644))
+
+	err := impl.importSnapshot(emptyFile)
+	assert.EqualError(t, err, "Snapshot "+emptyFile+" has no authors")
+	assert.Equal(t, "", impl.building, "The generation of a refused snapshot should be abandoned")
+	assert.Equal(t, "00000001", impl.active)
+	assertCount(t, service, 1)
+
+	snapshotFile, err := os.Create(getTempFile(t).Name())
+	assert.NoError(t, err)
+	defer os.Remove(snapshotFile.Name())
+	pv, _, err := service.getSnapshot()
+	assert.NoError(t, err)
+	_, err = io.Copy(snapshotFile, &pv)
+	assert.NoError(t, err)
+	snapshotFile.Close()
+
+	seeded := createAuthorService([]Taxonomy{{Name: "taxonomy_string", Repository: &dummyRepo{}}}, NewMemoryAuthorStore(), &mockClient{resp: []berthaAuthor{}}, ServiceOptions{Guard: ReloadGuard{MinCuratedMatches: 1}, Startup: StartupOptions{SeedSnapshot: snapshotFile.Name()}})
+	defer seeded.Shutdown()
+	waitTillInit(t, seeded)
+	waitTillReloadFinished(t, seeded)
+	assert.False(t, seeded.isDataLoaded(), "A snapshot refused by the reload guard should not be served")
+	generations, err := seeded.getGenerations()
+	assert.NoError(t, err)
+	for _, g := range generations {
+		assert.NotEqual(t, "active", g.Status)
+	}
+}
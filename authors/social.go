@@ -0,0 +1,203 @@
+package authors
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const (
+	networkTwitter   = "twitter"
+	networkFacebook  = "facebook"
+	networkLinkedin  = "linkedin"
+	networkMastodon  = "mastodon"
+	networkBluesky   = "bluesky"
+	networkInstagram = "instagram"
+	networkWebsite   = "website"
+)
+
+// socialNetworks are the networks of the social profiles, in the order they are listed
+var socialNetworks = []string{networkTwitter, networkFacebook, networkLinkedin, networkMastodon, networkBluesky, networkInstagram, networkWebsite}
+
+// legacyProfiles are the author fields which hold a social profile on their own, by network
+var legacyProfiles = map[string]func(a *author) *string{
+	networkTwitter:  func(a *author) *string { return &a.TwitterHandle },
+	networkFacebook: func(a *author) *string { return &a.FacebookProfile },
+	networkLinkedin: func(a *author) *string { return &a.LinkedinProfile },
+}
+
+var (
+	twitterHandle   = regexp.MustCompile(`^[A-Za-z0-9_]{1,15}$`)
+	facebookName    = regexp.MustCompile(`^([A-Za-z0-9.\-]{1,50}|profile\.php\?id=[0-9]+)$`)
+	linkedinName    = regexp.MustCompile(`^[A-Za-z0-9\-_%]{3,100}$`)
+	mastodonHandle  = regexp.MustCompile(`^@?([A-Za-z0-9_]{1,30})@([A-Za-z0-9\-.]+\.[A-Za-z]{2,})$`)
+	mastodonURL     = regexp.MustCompile(`^([A-Za-z0-9\-.]+\.[A-Za-z]{2,})/@([A-Za-z0-9_]{1,30})$`)
+	blueskyHandle   = regexp.MustCompile(`^([A-Za-z0-9\-]+\.)+[A-Za-z]{2,}$`)
+	instagramHandle = regexp.MustCompile(`^[A-Za-z0-9._]{1,30}$`)
+)
+
+// normaliseSocialProfile turns a handle, name or URL of a profile, in any of the usual shapes, into its canonical
+// handle and URL
+func normaliseSocialProfile(network string, value string) (socialProfile, error) {
+	value = strings.TrimSpace(value)
+	p := socialProfile{Network: network}
+	switch network {
+	case networkTwitter:
+		name := strings.TrimPrefix(profilePath(value, "twitter.com", "x.com"), "#!/")
+		name = strings.TrimPrefix(name, "@")
+		if !twitterHandle.MatchString(name) {
+			return p, fmt.Errorf("%v is not a valid Twitter handle", value)
+		}
+		p.Handle, p.URL = "@"+name, "https://twitter.com/"+name
+	case networkFacebook:
+		name := profilePath(value, "facebook.com", "fb.com")
+		if !facebookName.MatchString(name) {
+			return p, fmt.Errorf("%v is not a valid Facebook profile", value)
+		}
+		p.Handle, p.URL = name, "https://www.facebook.com/"+name
+	case networkLinkedin:
+		name := strings.TrimPrefix(profilePath(value, "linkedin.com"), "in/")
+		if !linkedinName.MatchString(name) {
+			return p, fmt.Errorf("%v is not a valid LinkedIn profile", value)
+		}
+		p.Handle, p.URL = name, "https://www.linkedin.com/in/"+name
+	case networkMastodon:
+		address := withoutScheme(value)
+		if m := mastodonURL.FindStringSubmatch(address); m != nil {
+			p.Handle, p.URL = "@"+m[2]+"@"+m[1], "https://"+m[1]+"/@"+m[2]
+		} else if m := mastodonHandle.FindStringSubmatch(address); m != nil {
+			p.Handle, p.URL = "@"+m[1]+"@"+m[2], "https://"+m[2]+"/@"+m[1]
+		} else {
+			return p, fmt.Errorf("%v is not a valid Mastodon account", value)
+		}
+	case networkBluesky:
+		name := strings.TrimPrefix(strings.TrimPrefix(profilePath(value, "bsky.app"), "profile/"), "@")
+		if !blueskyHandle.MatchString(name) {
+			return p, fmt.Errorf("%v is not a valid Bluesky handle", value)
+		}
+		p.Handle, p.URL = "@"+name, "https://bsky.app/profile/"+name
+	case networkInstagram:
+		name := strings.TrimPrefix(profilePath(value, "instagram.com"), "@")
+		if !instagramHandle.MatchString(name) {
+			return p, fmt.Errorf("%v is not a valid Instagram handle", value)
+		}
+		p.Handle, p.URL = "@"+name, "https://www.instagram.com/"+name
+	case networkWebsite:
+		website := value
+		if !strings.Contains(website, "://") {
+			website = "https://" + website
+		}
+		u, err := url.Parse(website)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || !strings.Contains(u.Host, ".") {
+			return p, fmt.Errorf("%v is not a valid website", value)
+		}
+		p.URL = u.String()
+	default:
+		return p, fmt.Errorf("%v is not a known social network", network)
+	}
+	return p, nil
+}
+
+// profilePath is the path of a profile URL on one of the hosts of a network or their subdomains, or the value
+// itself when it isn't such a URL
+func profilePath(value string, hosts ...string) string {
+	address := withoutScheme(value)
+	if i := strings.Index(address, "/"); i > 0 {
+		domain := strings.ToLower(address[:i])
+		for _, host := range hosts {
+			if domain == host || strings.HasSuffix(domain, "."+host) {
+				address = address[i+1:]
+				break
+			}
+		}
+	}
+	if i := strings.IndexAny(address, "?#"); i >= 0 && !strings.HasPrefix(address, "profile.php?") && !strings.HasPrefix(address, "#!/") {
+		address = address[:i]
+	}
+	return strings.Trim(address, "/")
+}
+
+func withoutScheme(value string) string {
+	lower := strings.ToLower(value)
+	for _, scheme := range []string{"https://", "http://"} {
+		if strings.HasPrefix(lower, scheme) {
+			return value[len(scheme):]
+		}
+	}
+	return value
+}
+
+// curatedProfiles are the social profile columns of the curated spreadsheet, by network
+func curatedProfiles(b berthaAuthor) map[string]string {
+	return map[string]string{
+		networkTwitter:   b.TwitterHandle,
+		networkFacebook:  b.FacebookProfile,
+		networkLinkedin:  b.LinkedinProfile,
+		networkMastodon:  b.Mastodon,
+		networkBluesky:   b.Bluesky,
+		networkInstagram: b.Instagram,
+		networkWebsite:   b.Website,
+	}
+}
+
+// curatedSocialProfiles normalises the social profiles of a curated author, returning the valid ones and
+// reporting the invalid ones
+func curatedSocialProfiles(b berthaAuthor, berthaUUID string) ([]socialProfile, []socialProfileReport) {
+	var profiles []socialProfile
+	var invalid []socialProfileReport
+	values := curatedProfiles(b)
+	for _, network := range socialNetworks {
+		if strings.TrimSpace(values[network]) == "" {
+			continue
+		}
+		p, err := normaliseSocialProfile(network, values[network])
+		if err != nil {
+			invalid = append(invalid, socialProfileReport{UUID: berthaUUID, TmeIdentifier: b.TmeIdentifier, Name: b.Name,
+				Network: network, Value: values[network], Error: err.Error()})
+			continue
+		}
+		profiles = append(profiles, p)
+	}
+	return profiles, invalid
+}
+
+// withSocialProfiles normalises the legacy social profile fields of an author and lists them with the
+// profiles of the other networks. Invalid legacy profiles are cleared.
+func withSocialProfiles(a author, others []socialProfile) author {
+	byNetwork := make(map[string]socialProfile)
+	for _, p := range others {
+		if _, legacy := legacyProfiles[p.Network]; !legacy {
+			byNetwork[p.Network] = p
+		}
+	}
+	for network, field := range legacyProfiles {
+		value := field(&a)
+		if *value == "" {
+			continue
+		}
+		p, err := normaliseSocialProfile(network, *value)
+		if err != nil {
+			*value = ""
+			continue
+		}
+		*value = legacyValue(p)
+		byNetwork[network] = p
+	}
+
+	a.SocialProfiles = nil
+	for _, network := range socialNetworks {
+		if p, ok := byNetwork[network]; ok {
+			a.SocialProfiles = append(a.SocialProfiles, p)
+		}
+	}
+	return a
+}
+
+// legacyValue is how a profile is kept in its legacy field: the handle for Twitter and the URL otherwise
+func legacyValue(p socialProfile) string {
+	if p.Network == networkTwitter {
+		return p.Handle
+	}
+	return p.URL
+}
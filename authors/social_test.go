@@ -0,0 +1,87 @@
+package authors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormaliseSocialProfile(t *testing.T) {
+	tests := []struct {
+		network string
+		value   string
+		handle  string
+		url     string
+		err     string
+	}{
+		{"twitter", "@martinwolf_", "@martinwolf_", "https://twitter.com/martinwolf_", ""},
+		{"twitter", "martinwolf_", "@martinwolf_", "https://twitter.com/martinwolf_", ""},
+		{"twitter", "https://twitter.com/martinwolf_?lang=en", "@martinwolf_", "https://twitter.com/martinwolf_", ""},
+		{"twitter", "http://www.twitter.com/#!/martinwolf_", "@martinwolf_", "https://twitter.com/martinwolf_", ""},
+		{"twitter", "x.com/martinwolf_/", "@martinwolf_", "https://twitter.com/martinwolf_", ""},
+		{"twitter", "@martin wolf", "", "", "@martin wolf is not a valid Twitter handle"},
+		{"facebook", "/terryorange", "terryorange", "https://www.facebook.com/terryorange", ""},
+		{"facebook", "https://m.facebook.com/terry.orange", "terry.orange", "https://www.facebook.com/terry.orange", ""},
+		{"facebook", "facebook.com/profile.php?id=1234", "profile.php?id=1234", "https://www.facebook.com/profile.php?id=1234", ""},
+		{"facebook", "terry orange", "", "", "terry orange is not a valid Facebook profile"},
+		{"linkedin", "terryorange", "terryorange", "https://www.linkedin.com/in/terryorange", ""},
+		{"linkedin", "https://uk.linkedin.com/in/terry-orange-1234/", "terry-orange-1234", "https://www.linkedin.com/in/terry-orange-1234", ""},
+		{"linkedin", "https://www.linkedin.com/company/ft", "", "", "https://www.linkedin.com/company/ft is not a valid LinkedIn profile"},
+		{"linkedin", "linkedin.com/in/terry-orange-1234", "terry-orange-1234", "https://www.linkedin.com/in/terry-orange-1234", ""},
+		{"mastodon", "@martinwolf@mastodon.social", "@martinwolf@mastodon.social", "https://mastodon.social/@martinwolf", ""},
+		{"mastodon", "https://mastodon.social/@martinwolf", "@martinwolf@mastodon.social", "https://mastodon.social/@martinwolf", ""},
+		{"mastodon", "@martinwolf", "", "", "@martinwolf is not a valid Mastodon account"},
+		{"bluesky", "@martinwolf.bsky.social", "@martinwolf.bsky.social", "https://bsky.app/profile/martinwolf.bsky.social", ""},
+		{"bluesky", "https://bsky.app/profile/martinwolf.ft.com", "@martinwolf.ft.com", "https://bsky.app/profile/martinwolf.ft.com", ""},
+		{"bluesky", "martinwolf", "", "", "martinwolf is not a valid Bluesky handle"},
+		{"instagram", "instagram.com/martin.wolf", "@martin.wolf", "https://www.instagram.com/martin.wolf", ""},
+		{"website", "martinwolf.com", "", "https://martinwolf.com", ""},
+		{"website", "http://www.martinwolf.com/about", "", "http://www.martinwolf.com/about", ""},
+		{"website", "ftp://martinwolf.com", "", "", "ftp://martinwolf.com is not a valid website"},
+		{"myspace", "martinwolf", "", "", "myspace is not a known social network"},
+	}
+
+	for _, test := range tests {
+		p, err := normaliseSocialProfile(test.network, test.value)
+		if test.err != "" {
+			assert.EqualError(t, err, test.err, test.value)
+			continue
+		}
+		assert.NoError(t, err, test.value)
+		assert.Equal(t, socialProfile{Network: test.network, Handle: test.handle, URL: test.url}, p, test.value)
+	}
+}
+
+func TestCuratedSocialProfiles(t *testing.T) {
+	b := berthaAuthor{Name: "Terry Orange", TmeIdentifier: "1234567890", TwitterHandle: "terryorange", FacebookProfile: "terry orange",
+		Bluesky: "terryorange.bsky.social", Website: "terryorange.com"}
+	a, err := berthaToAuthor(b, UUIDStrategy{})
+	assert.NoError(t, err)
+	assert.Equal(t, "@terryorange", a.TwitterHandle)
+	assert.Equal(t, "", a.FacebookProfile)
+	assert.Equal(t, []socialProfile{
+		{Network: "twitter", Handle: "@terryorange", URL: "https://twitter.com/terryorange"},
+		{Network: "bluesky", Handle: "@terryorange.bsky.social", URL: "https://bsky.app/profile/terryorange.bsky.social"},
+		{Network: "website", URL: "https://terryorange.com"},
+	}, a.SocialProfiles)
+
+	_, invalid := curatedSocialProfiles(b, a.UUID)
+	assert.Equal(t, []socialProfileReport{{UUID: a.UUID, TmeIdentifier: "1234567890", Name: "Terry Orange", Network: "facebook",
+		Value: "terry orange", Error: "terry orange is not a valid Facebook profile"}}, invalid)
+}
+
+func TestOverridingSocialProfiles(t *testing.T) {
+	a := withSocialProfiles(author{TwitterHandle: "@terryorange", LinkedinProfile: "terryorange"}, []socialProfile{{Network: "website", URL: "https://terryorange.com"}})
+	overridden, err := applyOverride(a, authorOverride{Fields: map[string]string{"twitterHandle": "https://twitter.com/terry", "linkedinProfile": ""}})
+	assert.NoError(t, err)
+	assert.Equal(t, "@terry", overridden.TwitterHandle)
+	assert.Equal(t, "", overridden.LinkedinProfile)
+	assert.Equal(t, []socialProfile{
+		{Network: "twitter", Handle: "@terry", URL: "https://twitter.com/terry"},
+		{Network: "website", URL: "https://terryorange.com"},
+	}, overridden.SocialProfiles)
+
+	invalid := "terry orange"
+	assert.EqualError(t, validateOverrideRequest(overrideRequest{Fields: map[string]*string{"facebookProfile": &invalid}, User: "jane.doe", Reason: "New profile"}),
+		"terry orange is not a valid Facebook profile")
+}
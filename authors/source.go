@@ -0,0 +1,110 @@
+package authors
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"time"
+)
+
+// tmeSourceOf is the raw TME term an author was transformed from
+func tmeSourceOf(t term, tmeIdentifier string, taxonomyName string) tmeSource {
+	return tmeSource{TmeIdentifier: tmeIdentifier, Taxonomy: taxonomyName, XML: "<term>" + t.Raw + "</term>"}
+}
+
+// readSource reads the raw sources of an author from the source bucket, or an empty one when there are none yet
+func readSource(bucket StoreBucket, uuid string) (authorSource, error) {
+	src := authorSource{UUID: uuid}
+	cachedSource := bucket.Get([]byte(uuid))
+	if cachedSource == nil {
+		return src, nil
+	}
+	err := json.Unmarshal(cachedSource, &src)
+	return src, err
+}
+
+func putSource(bucket StoreBucket, src authorSource) error {
+	marshalledSource, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return bucket.Put([]byte(src.UUID), marshalledSource)
+}
+
+// addTmeSource adds a raw TME term to the sources of an author
+func addTmeSource(bucket StoreBucket, uuid string, tme tmeSource) error {
+	src, err := readSource(bucket, uuid)
+	if err != nil {
+		return err
+	}
+	src.TME = append(src.TME, tme)
+	return putSource(bucket, src)
+}
+
+// mergeSources moves the raw sources of a duplicate author to its canonical author
+func mergeSources(bucket StoreBucket, canonicalUUID string, secondaryUUID string) error {
+	canonical, err := readSource(bucket, canonicalUUID)
+	if err != nil {
+		return err
+	}
+	secondary, err := readSource(bucket, secondaryUUID)
+	if err != nil {
+		return err
+	}
+	canonical.TME = append(canonical.TME, secondary.TME...)
+	if canonical.Bertha == nil {
+		canonical.Bertha = secondary.Bertha
+	}
+	if err := putSource(bucket, canonical); err != nil {
+		return err
+	}
+	return bucket.Delete([]byte(secondaryUUID))
+}
+
+// deriveAuthor transforms the raw sources of an author again, as a reload does: the first TME term merged with the
+// other TME terms, then with the curated author from the given row of the Bertha payload. Its provenance says the
+// sources were loaded at loadedAt.
+func (s *authorServiceImpl) deriveAuthor(src authorSource, row int, loadedAt time.Time) (author, authorProvenance, error) {
+	var a author
+	p := authorProvenance{UUID: src.UUID}
+	for i, tme := range src.TME {
+		var t term
+		if err := xml.Unmarshal([]byte(tme.XML), &t); err != nil {
+			return a, p, err
+		}
+		transformed := transformAuthor(t, tme.Taxonomy, s.aliasOptions, s.uuids)
+		if i == 0 {
+			a = transformed
+			p = tmeProvenance(a, loadedAt)
+			continue
+		}
+		a = mergeSecondary(a, transformed)
+	}
+	if src.Bertha == nil {
+		return a, p, nil
+	}
+
+	var b berthaAuthor
+	if err := json.Unmarshal(src.Bertha, &b); err != nil {
+		return a, p, err
+	}
+	b.Raw = src.Bertha
+	b, _ = sanitiseBiography(b, src.UUID)
+	b, _ = s.checkImage(b, src.UUID)
+	if len(src.TME) == 0 {
+		curated, err := berthaToAuthor(b, s.uuids)
+		if err != nil {
+			return a, p, err
+		}
+		a = curated
+		p.addCuratedProvenance(curatedSources(curatedOnlyFields), b, row, loadedAt)
+	} else {
+		merged, sources, err := addBerthaInformation(a, b, s.mergePolicy, s.uuids)
+		if err != nil {
+			return a, p, err
+		}
+		a = merged
+		p.addCuratedProvenance(sources, b, row, loadedAt)
+	}
+	a.Aliases = completeAliases(a.Aliases, a.Name, s.aliasOptions)
+	return a, p, nil
+}
@@ -0,0 +1,124 @@
+package authors
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// BoltStore keeps the authors in a bolt file which outlives restarts
+	BoltStore = "bolt"
+	// MemoryStore keeps the authors in memory only, each start loads them again
+	MemoryStore = "memory"
+)
+
+// AuthorStore - where the generations of authors and the overrides which outlive them are stored.
+// Top level buckets, such as the overrides, hold data shared by every generation. Each generation
+// has buckets of its own, such as its authors.
+type AuthorStore interface {
+	// Open makes the store ready to use, it can be called again once it is
+	Open() error
+	// View runs a function with a consistent, read-only view of the store
+	View(fn func(tx StoreTx) error) error
+	// Update runs a function whose changes are all kept, or all dropped when it fails
+	Update(fn func(tx StoreTx) error) error
+	// Batch is Update, but the changes of concurrent calls can be committed together, in which case the
+	// function can be run more than once
+	Batch(fn func(tx StoreTx) error) error
+	Close() error
+}
+
+// StoreTx - a transaction of an AuthorStore
+type StoreTx interface {
+	// Bucket is a top level bucket, nil when there's none
+	Bucket(name string) StoreBucket
+	// Generation is a generation, nil when there's none
+	Generation(id string) StoreGeneration
+	// CreateGeneration creates a generation with empty buckets
+	CreateGeneration(id string, buckets []string) (StoreGeneration, error)
+	// DeleteGeneration deletes a generation and its buckets, if there is one
+	DeleteGeneration(id string) error
+	// NextSequence is a number which was never returned before, to number generations
+	NextSequence() (uint64, error)
+}
+
+// StoreGeneration - the buckets of a generation of authors
+type StoreGeneration interface {
+	// Bucket is a bucket of the generation, nil when there's none
+	Bucket(name string) StoreBucket
+}
+
+// StoreBucket - a bucket of values sorted by their key. Values can only be used during the transaction
+// which read them and must not be modified.
+type StoreBucket interface {
+	Get(key []byte) []byte
+	Put(key []byte, value []byte) error
+	Delete(key []byte) error
+	// ForEach calls a function with every key and value, in key order
+	ForEach(fn func(k, v []byte) error) error
+	Cursor() StoreCursor
+	// Count is how many keys the bucket has
+	Count() int
+}
+
+// StoreCursor - walks the keys of a bucket in order. It answers nil keys past either end.
+type StoreCursor interface {
+	First() ([]byte, []byte)
+	Last() ([]byte, []byte)
+	Next() ([]byte, []byte)
+	Prev() ([]byte, []byte)
+}
+
+// compactor is implemented by the stores which need compacting to give back the space of deleted generations
+type compactor interface {
+	Compact(generations []string) (int64, int64, error)
+}
+
+// NewAuthorStore - create the store of a kind, for a cache file when it is kept in a file
+func NewAuthorStore(kind string, cacheFileName string) (AuthorStore, error) {
+	switch strings.ToLower(kind) {
+	case BoltStore:
+		return NewBoltAuthorStore(cacheFileName), nil
+	case MemoryStore:
+		return NewMemoryAuthorStore(), nil
+	}
+	return nil, fmt.Errorf("Unsupported store %v, it should be %v or %v", kind, BoltStore, MemoryStore)
+}
+
+// topLevelBuckets are the buckets shared by every generation
+var topLevelBuckets = []string{generationInfoBucket, stateBucket, overrideBucket}
+
+// compact compacts the store, when it can be, and reports the size of the cache file before and after
+func (s *authorServiceImpl) compact() {
+	c, ok := s.store.(compactor)
+	if !ok {
+		return
+	}
+	result := compaction{StartedAt: time.Now()}
+	var kept []string
+	err := s.store.View(func(tx StoreTx) error {
+		var err error
+		kept, err = compactedGenerations(tx, s.keptGenerations)
+		return err
+	})
+	if err == nil {
+		result.SizeBefore, result.SizeAfter, err = c.Compact(kept)
+	}
+	result.FinishedAt = time.Now()
+	if err != nil {
+		log.Errorf("Error while compacting the cache: [%v]", err.Error())
+		result.Error = err.Error()
+	} else {
+		log.Infof("Compacted the cache from %d to %d bytes in %v.", result.SizeBefore, result.SizeAfter, result.FinishedAt.Sub(result.StartedAt))
+	}
+	metrics.GetOrRegisterGauge("cache.size.beforeCompaction", metrics.DefaultRegistry).Update(result.SizeBefore)
+	metrics.GetOrRegisterGauge("cache.size.afterCompaction", metrics.DefaultRegistry).Update(result.SizeAfter)
+
+	s.Lock()
+	defer s.Unlock()
+	s.report.Compaction = &result
+}
@@ -0,0 +1,282 @@
+package authors
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testStores(t *testing.T) (map[string]AuthorStore, func()) {
+	tmpfile := getTempFile(t)
+	stores := map[string]AuthorStore{BoltStore: NewBoltAuthorStore(tmpfile.Name()), MemoryStore: NewMemoryAuthorStore()}
+	for kind, store := range stores {
+		assert.NoError(t, store.Open(), kind)
+	}
+	return stores, func() {
+		for _, store := range stores {
+			store.Close()
+		}
+		os.Remove(tmpfile.Name())
+	}
+}
+
+func TestStoreBuckets(t *testing.T) {
+	stores, closeStores := testStores(t)
+	defer closeStores()
+	for kind, store := range stores {
+		assert.NoError(t, store.Update(func(tx StoreTx) error {
+			bucket := tx.Bucket(overrideBucket)
+			for _, k := range []string{"b", "c", "a"} {
+				if err := bucket.Put([]byte(k), []byte("value of "+k)); err != nil {
+					return err
+				}
+			}
+			return bucket.Delete([]byte("c"))
+		}), kind)
+
+		assert.NoError(t, store.View(func(tx StoreTx) error {
+			bucket := tx.Bucket(overrideBucket)
+			assert.Equal(t, "value of a", string(bucket.Get([]byte("a"))), kind)
+			assert.Nil(t, bucket.Get([]byte("c")), kind)
+			assert.Equal(t, 2, bucket.Count(), kind)
+			assert.Nil(t, tx.Bucket("unknown"), kind)
+			assert.Error(t, bucket.Put([]byte("d"), []byte("value of d")), "%v should be read-only", kind)
+
+			var keys []string
+			bucket.ForEach(func(k, v []byte) error {
+				keys = append(keys, string(k))
+				return nil
+			})
+			assert.Equal(t, []string{"a", "b"}, keys, kind)
+
+			c := bucket.Cursor()
+			k, v := c.Last()
+			assert.Equal(t, "b", string(k), kind)
+			assert.Equal(t, "value of b", string(v), kind)
+			k, _ = c.Prev()
+			assert.Equal(t, "a", string(k), kind)
+			k, _ = c.Prev()
+			assert.Nil(t, k, kind)
+			k, _ = c.First()
+			assert.Equal(t, "a", string(k), kind)
+			return nil
+		}), kind)
+	}
+}
+
+func TestStoreUndoesFailedUpdates(t *testing.T) {
+	stores, closeStores := testStores(t)
+	defer closeStores()
+	for kind, store := range stores {
+		err := store.Update(func(tx StoreTx) error {
+			if err := tx.Bucket(overrideBucket).Put([]byte("a"), []byte("value of a")); err != nil {
+				return err
+			}
+			if _, err := tx.CreateGeneration("00000001", generationBuckets); err != nil {
+				return err
+			}
+			return errors.New("Failed update")
+		})
+		assert.EqualError(t, err, "Failed update", kind)
+
+		assert.NoError(t, store.View(func(tx StoreTx) error {
+			assert.Nil(t, tx.Bucket(overrideBucket).Get([]byte("a")), kind)
+			assert.Nil(t, tx.Generation("00000001"), kind)
+			return nil
+		}), kind)
+	}
+}
+
+func TestStoreGenerations(t *testing.T) {
+	stores, closeStores := testStores(t)
+	defer closeStores()
+	for kind, store := range stores {
+		assert.NoError(t, store.Update(func(tx StoreTx) error {
+			for _, expected := range []uint64{1, 2} {
+				seq, err := tx.NextSequence()
+				assert.NoError(t, err, kind)
+				assert.Equal(t, expected, seq, kind)
+			}
+			gen, err := tx.CreateGeneration("00000001", generationBuckets)
+			if err != nil {
+				return err
+			}
+			if err := gen.Bucket(cacheBucket).Put([]byte(testUUID), []byte("{}")); err != nil {
+				return err
+			}
+			_, err = tx.CreateGeneration("00000002", generationBuckets)
+			return err
+		}), kind)
+		assert.NoError(t, store.Update(func(tx StoreTx) error {
+			return tx.DeleteGeneration("00000002")
+		}), kind)
+
+		assert.NoError(t, store.View(func(tx StoreTx) error {
+			gen := tx.Generation("00000001")
+			if assert.NotNil(t, gen, kind) {
+				assert.Equal(t, 1, gen.Bucket(cacheBucket).Count(), kind)
+				assert.Equal(t, 0, gen.Bucket(sourceBucket).Count(), kind)
+			}
+			assert.Nil(t, tx.Generation("00000002"), kind)
+			return nil
+		}), kind)
+	}
+}
+
+func TestMemoryStoreUpdatesWhileViewing(t *testing.T) {
+	store := NewMemoryAuthorStore()
+	assert.NoError(t, store.Open())
+	assert.NoError(t, store.Update(func(tx StoreTx) error {
+		return tx.Bucket(overrideBucket).Put([]byte("a"), []byte("value of a"))
+	}))
+
+	viewing := make(chan struct{})
+	updated := make(chan error)
+	goOn := make(chan struct{})
+	viewed := make(chan struct{})
+	go func() {
+		assert.NoError(t, store.View(func(tx StoreTx) error {
+			close(viewing)
+			<-goOn
+			bucket := tx.Bucket(overrideBucket)
+			assert.Equal(t, "value of a", string(bucket.Get([]byte("a"))), "The view should keep reading the authors it started with")
+			assert.Nil(t, bucket.Get([]byte("b")))
+			return nil
+		}))
+		close(viewed)
+	}()
+	<-viewing
+	go func() {
+		updated <- store.Update(func(tx StoreTx) error {
+			bucket := tx.Bucket(overrideBucket)
+			if err := bucket.Delete([]byte("a")); err != nil {
+				return err
+			}
+			return bucket.Put([]byte("b"), []byte("value of b"))
+		})
+	}()
+	select {
+	case err := <-updated:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("The update waited for the view to finish")
+	}
+	close(goOn)
+	<-viewed
+
+	assert.NoError(t, store.View(func(tx StoreTx) error {
+		bucket := tx.Bucket(overrideBucket)
+		assert.Nil(t, bucket.Get([]byte("a")))
+		assert.Equal(t, "value of b", string(bucket.Get([]byte("b"))))
+		return nil
+	}))
+}
+
+func TestBoltStoreCompaction(t *testing.T) {
+	tmpfile := getTempFile(t)
+	defer os.Remove(tmpfile.Name())
+	store := NewBoltAuthorStore(tmpfile.Name())
+	assert.NoError(t, store.Open())
+	defer store.Close()
+	value := bytes.Repeat([]byte("x"), 1024)
+	for _, id := range []string{"00000001", "00000002", "00000003"} {
+		assert.NoError(t, store.Update(func(tx StoreTx) error {
+			if _, err := tx.NextSequence(); err != nil {
+				return err
+			}
+			if err := putGeneration(tx, generation{ID: id}); err != nil {
+				return err
+			}
+			gen, err := tx.CreateGeneration(id, generationBuckets)
+			if err != nil {
+				return err
+			}
+			for i := 0; i < 1000; i++ {
+				if err := gen.Bucket(cacheBucket).Put([]byte(fmt.Sprintf("%05d", i)), value); err != nil {
+					return err
+				}
+			}
+			return nil
+		}))
+	}
+	assert.NoError(t, store.Update(func(tx StoreTx) error {
+		if err := tx.Bucket(overrideBucket).Put([]byte("a"), []byte("value of a")); err != nil {
+			return err
+		}
+		return tx.DeleteGeneration("00000001")
+	}))
+
+	before, after, err := store.Compact([]string{"00000002"})
+	assert.NoError(t, err)
+	assert.True(t, after < before, "The file should shrink from %d bytes, not be %d bytes", before, after)
+	_, err = os.Stat(tmpfile.Name() + ".compacting")
+	assert.True(t, os.IsNotExist(err))
+
+	assert.NoError(t, store.Update(func(tx StoreTx) error {
+		assert.Nil(t, tx.Generation("00000001"))
+		assert.Nil(t, tx.Generation("00000003"), "The generations left out should be dropped")
+		assert.Nil(t, tx.Bucket(generationInfoBucket).Get([]byte("00000003")))
+		if gen := tx.Generation("00000002"); assert.NotNil(t, gen) {
+			assert.Equal(t, 1000, gen.Bucket(cacheBucket).Count())
+			assert.Equal(t, value, gen.Bucket(cacheBucket).Get([]byte("00999")))
+		}
+		assert.NotNil(t, tx.Bucket(generationInfoBucket).Get([]byte("00000002")))
+		assert.Equal(t, "value of a", string(tx.Bucket(overrideBucket).Get([]byte("a"))))
+		seq, err := tx.NextSequence()
+		assert.Equal(t, uint64(4), seq, "The sequence of the generations should be kept")
+		return err
+	}))
+}
+
+func TestBoltStoreFailedCompactionKeepsTheFile(t *testing.T) {
+	tmpfile := getTempFile(t)
+	defer os.Remove(tmpfile.Name())
+	store := NewBoltAuthorStore(tmpfile.Name())
+	assert.NoError(t, store.Open())
+	assert.NoError(t, store.Update(func(tx StoreTx) error {
+		return tx.Bucket(overrideBucket).Put([]byte("a"), []byte("value of a"))
+	}))
+
+	defer func() {
+		rename = os.Rename
+	}()
+	rename = func(from string, to string) error {
+		if to == tmpfile.Name() && from != tmpfile.Name()+".original" {
+			return errors.New("Failed rename")
+		}
+		return os.Rename(from, to)
+	}
+	_, _, err := store.Compact(nil)
+	assert.EqualError(t, err, "Failed rename")
+	for _, suffix := range []string{".compacting", ".original"} {
+		_, err = os.Stat(tmpfile.Name() + suffix)
+		assert.True(t, os.IsNotExist(err), suffix)
+	}
+
+	assert.NoError(t, store.Update(func(tx StoreTx) error {
+		assert.Equal(t, "value of a", string(tx.Bucket(overrideBucket).Get([]byte("a"))))
+		return tx.Bucket(overrideBucket).Put([]byte("b"), []byte("value of b"))
+	}))
+	assert.NoError(t, store.Close())
+
+	reopened := NewBoltAuthorStore(tmpfile.Name())
+	assert.NoError(t, reopened.Open(), "The original file should still be there")
+	defer reopened.Close()
+	assert.NoError(t, reopened.View(func(tx StoreTx) error {
+		assert.Equal(t, "value of b", string(tx.Bucket(overrideBucket).Get([]byte("b"))))
+		return nil
+	}))
+}
+
+func TestNewAuthorStore(t *testing.T) {
+	store, err := NewAuthorStore("Memory", "")
+	assert.NoError(t, err)
+	assert.IsType(t, &MemoryAuthorStore{}, store)
+	_, err = NewAuthorStore("redis", "")
+	assert.EqualError(t, err, "Unsupported store redis, it should be bolt or memory")
+}
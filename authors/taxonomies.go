@@ -0,0 +1,35 @@
+package authors
+
+import (
+	"encoding/base64"
+	"strings"
+
+	"github.com/Financial-Times/tme-reader/tmereader"
+)
+
+// Taxonomy - a TME taxonomy of people and the repository its terms are read from
+type Taxonomy struct {
+	Name       string
+	Repository tmereader.Repository
+}
+
+// termIdentity is the part of a TME identifier which identifies the TME term, whatever its taxonomy
+func termIdentity(tmeIdentifier string) string {
+	if i := strings.LastIndex(tmeIdentifier, "-"); i >= 0 {
+		return tmeIdentifier[:i]
+	}
+	return tmeIdentifier
+}
+
+// taxonomyOf is the name of the TME taxonomy of a TME identifier, as built by buildTmeIdentifier
+func taxonomyOf(tmeIdentifier string) string {
+	i := strings.LastIndex(tmeIdentifier, "-")
+	if i < 0 {
+		return ""
+	}
+	name, err := base64.StdEncoding.DecodeString(tmeIdentifier[i+1:])
+	if err != nil {
+		return ""
+	}
+	return string(name)
+}
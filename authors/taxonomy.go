@@ -8,6 +8,7 @@ type term struct {
 	CanonicalName string  `xml:"name"`
 	RawID         string  `xml:"id"`
 	Aliases       aliases `xml:"variations"`
+	Raw           string  `xml:",innerxml"`
 }
 
 type aliases struct {
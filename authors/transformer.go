@@ -3,8 +3,6 @@ package authors
 import (
 	"encoding/base64"
 	"encoding/xml"
-
-	"github.com/pborman/uuid"
 )
 
 // AuthorTransformer struct
@@ -35,11 +33,11 @@ func (*AuthorTransformer) UnMarshallTerm(content []byte) (interface{}, error) {
 	return dummyTerm, nil
 }
 
-func transformAuthor(tmeTerm term, taxonomyName string) author {
+func transformAuthor(tmeTerm term, taxonomyName string, aliasOptions AliasOptions, uuids UUIDStrategy) author {
 	tmeIdentifier := buildTmeIdentifier(tmeTerm.RawID, taxonomyName)
-	authorUUID := uuid.NewMD5(uuid.UUID{}, []byte(tmeIdentifier)).String()
-	aliasList := buildAliasList(tmeTerm.Aliases, tmeTerm.CanonicalName)
-	return author{
+	authorUUID := uuids.uuidOf(tmeIdentifier)
+	aliasList := buildAliasList(tmeTerm.Aliases, tmeTerm.CanonicalName, aliasOptions)
+	return withParsedName(author{
 		UUID:      authorUUID,
 		Name:      tmeTerm.CanonicalName,
 		PrefLabel: tmeTerm.CanonicalName,
@@ -47,9 +45,10 @@ func transformAuthor(tmeTerm term, taxonomyName string) author {
 			TME:   []string{tmeIdentifier},
 			UUIDs: []string{authorUUID},
 		},
-		Type:    "Person",
-		Aliases: aliasList,
-	}
+		Type:       "Person",
+		Taxonomies: []string{taxonomyName},
+		Aliases:    aliasList,
+	})
 }
 
 func buildTmeIdentifier(rawID string, tmeTermTaxonomyName string) string {
@@ -70,12 +69,10 @@ func removeDuplicates(slice []string) []string {
 	return newSlice
 }
 
-func buildAliasList(aList aliases, canonicalName string) []string {
+func buildAliasList(aList aliases, canonicalName string, opts AliasOptions) []string {
 	aliasList := make([]string, len(aList.Alias))
 	for k, v := range aList.Alias {
 		aliasList[k] = v.Name
 	}
-	aliasList = append(aliasList, canonicalName)
-	aliasList = removeDuplicates(aliasList)
-	return aliasList
+	return completeAliases(aliasList, canonicalName, opts)
 }
@@ -21,12 +21,13 @@ func TestTransformAuthor(t *testing.T) {
 				{Name: "b"},
 			}},
 	}
-	tfp := transformAuthor(testTerm, taxonomyName)
+	tfp := transformAuthor(testTerm, taxonomyName, AliasOptions{}, UUIDStrategy{})
 	log.Infof("got author %v", tfp)
 	assert.NotNil(t, tfp)
-	assert.EqualValues(t, []string{"B", "b", "Bob"}, tfp.Aliases)
+	assert.EqualValues(t, []string{"B", "Bob"}, tfp.Aliases)
 	assert.Equal(t, "0e86d39b-8320-3a98-a87a-ff35d2cb04b9", tfp.UUID)
 	assert.Equal(t, "Bob", tfp.PrefLabel)
+	assert.Equal(t, []string{taxonomyName}, tfp.Taxonomies)
 }
 
 func TestDeduplication(t *testing.T) {
@@ -47,6 +48,6 @@ func TestBuildAliasList(t *testing.T) {
 
 	expectedOutput := []string{"A", "B", "C"}
 
-	actualOutput := buildAliasList(inputAliases, inputName)
+	actualOutput := buildAliasList(inputAliases, inputName, AliasOptions{})
 	assert.EqualValues(t, expectedOutput, actualOutput)
 }
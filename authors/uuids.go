@@ -0,0 +1,82 @@
+package authors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/pborman/uuid"
+)
+
+// DefaultUUIDNamespace is the namespace of v5 author UUIDs when no other namespace is configured
+var DefaultUUIDNamespace = uuid.NewSHA1(uuid.NameSpace_URL, []byte("http://api.ft.com/things/authors"))
+
+// UUIDStrategy - how the UUID of an author is derived from its TME identifier. By default it's the MD5
+// UUID of the TME identifier, authors of the V5Taxonomies get a v5 UUID in Namespace instead and
+// Legacy UUIDs win over both.
+type UUIDStrategy struct {
+	// Namespace is the namespace of v5 UUIDs. The default namespace is used when it's nil.
+	Namespace uuid.UUID
+	// V5Taxonomies are the names of the TME taxonomies whose authors get v5 UUIDs
+	V5Taxonomies []string
+	// Legacy are UUIDs which have to be kept, by TME identifier
+	Legacy map[string]string
+}
+
+// LoadUUIDStrategy - build a UUID strategy, reading the legacy UUIDs from a JSON file keyed by TME identifier
+func LoadUUIDStrategy(namespace string, v5Taxonomies []string, legacyFileName string) (UUIDStrategy, error) {
+	s := UUIDStrategy{V5Taxonomies: v5Taxonomies, Legacy: map[string]string{}}
+	if namespace != "" {
+		if s.Namespace = uuid.Parse(namespace); s.Namespace == nil {
+			return s, fmt.Errorf("UUID namespace %v is not a UUID", namespace)
+		}
+	}
+	if legacyFileName == "" {
+		return s, nil
+	}
+	contents, err := ioutil.ReadFile(legacyFileName)
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(contents, &s.Legacy); err != nil {
+		return s, fmt.Errorf("Legacy UUIDs %v are not valid JSON: %v", legacyFileName, err)
+	}
+	for tmeIdentifier, legacyUUID := range s.Legacy {
+		if uuid.Parse(legacyUUID) == nil {
+			return s, fmt.Errorf("Legacy UUID %v of %v is not a UUID", legacyUUID, tmeIdentifier)
+		}
+	}
+	return s, nil
+}
+
+// uuidOf derives the UUID of the author with a TME identifier
+func (s UUIDStrategy) uuidOf(tmeIdentifier string) string {
+	if legacyUUID, ok := s.Legacy[tmeIdentifier]; ok {
+		return strings.ToLower(legacyUUID)
+	}
+	if contains(s.V5Taxonomies, taxonomyOf(tmeIdentifier)) {
+		namespace := s.Namespace
+		if namespace == nil {
+			namespace = DefaultUUIDNamespace
+		}
+		return uuid.NewSHA1(namespace, []byte(tmeIdentifier)).String()
+	}
+	return uuid.NewMD5(uuid.UUID{}, []byte(tmeIdentifier)).String()
+}
+
+// uuidMismatches are the stored authors whose UUID isn't the one the strategy gives their TME identifier
+func (s UUIDStrategy) uuidMismatches(authors []author) []uuidMismatch {
+	mismatches := []uuidMismatch{}
+	for _, a := range authors {
+		if len(a.AlternativeIdentifiers.TME) == 0 {
+			mismatches = append(mismatches, uuidMismatch{UUID: a.UUID, PrefLabel: a.PrefLabel})
+			continue
+		}
+		tmeIdentifier := a.AlternativeIdentifiers.TME[0]
+		if expected := s.uuidOf(tmeIdentifier); expected != a.UUID {
+			mismatches = append(mismatches, uuidMismatch{UUID: a.UUID, PrefLabel: a.PrefLabel, TmeIdentifier: tmeIdentifier, ExpectedUUID: expected})
+		}
+	}
+	return mismatches
+}
@@ -0,0 +1,74 @@
+package authors
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUUIDOf(t *testing.T) {
+	authorsID := buildTmeIdentifier("wolf", "Authors")
+	contributorsID := buildTmeIdentifier("wolf", "Contributors")
+	namespace := uuid.Parse("6ba7b811-9dad-11d1-80b4-00c04fd430c8")
+
+	tests := []struct {
+		name          string
+		strategy      UUIDStrategy
+		tmeIdentifier string
+		expected      string
+	}{
+		{"MD5 by default", UUIDStrategy{}, authorsID, uuid.NewMD5(uuid.UUID{}, []byte(authorsID)).String()},
+		{"MD5 for other taxonomies", UUIDStrategy{V5Taxonomies: []string{"Contributors"}}, authorsID, uuid.NewMD5(uuid.UUID{}, []byte(authorsID)).String()},
+		{"v5 in the default namespace", UUIDStrategy{V5Taxonomies: []string{"Contributors"}}, contributorsID,
+			uuid.NewSHA1(DefaultUUIDNamespace, []byte(contributorsID)).String()},
+		{"v5 in a namespace", UUIDStrategy{Namespace: namespace, V5Taxonomies: []string{"Contributors"}}, contributorsID,
+			uuid.NewSHA1(namespace, []byte(contributorsID)).String()},
+		{"Legacy UUID", UUIDStrategy{V5Taxonomies: []string{"Contributors"}, Legacy: map[string]string{contributorsID: "0F07D468-FC37-3C44-BF19-A81F2AAE9F36"}},
+			contributorsID, "0f07d468-fc37-3c44-bf19-a81f2aae9f36"},
+		{"Identifier without taxonomy", UUIDStrategy{V5Taxonomies: []string{"Contributors"}}, "1234567890", uuid.NewMD5(uuid.UUID{}, []byte("1234567890")).String()},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.strategy.uuidOf(test.tmeIdentifier), test.name)
+	}
+}
+
+func TestLoadUUIDStrategy(t *testing.T) {
+	legacyFile, err := ioutil.TempFile("", "legacy")
+	assert.NoError(t, err)
+	defer os.Remove(legacyFile.Name())
+	_, err = legacyFile.WriteString(`{"MTIzNA==-QXV0aG9ycw==": "0f07d468-fc37-3c44-bf19-a81f2aae9f36"}`)
+	assert.NoError(t, err)
+	assert.NoError(t, legacyFile.Close())
+
+	s, err := LoadUUIDStrategy("6ba7b811-9dad-11d1-80b4-00c04fd430c8", []string{"Contributors"}, legacyFile.Name())
+	assert.NoError(t, err)
+	assert.Equal(t, "6ba7b811-9dad-11d1-80b4-00c04fd430c8", s.Namespace.String())
+	assert.Equal(t, map[string]string{"MTIzNA==-QXV0aG9ycw==": "0f07d468-fc37-3c44-bf19-a81f2aae9f36"}, s.Legacy)
+
+	_, err = LoadUUIDStrategy("authors", nil, "")
+	assert.EqualError(t, err, "UUID namespace authors is not a UUID")
+
+	assert.NoError(t, ioutil.WriteFile(legacyFile.Name(), []byte(`{"MTIzNA==-QXV0aG9ycw==": "wolf"}`), 0644))
+	_, err = LoadUUIDStrategy("", nil, legacyFile.Name())
+	assert.EqualError(t, err, "Legacy UUID wolf of MTIzNA==-QXV0aG9ycw== is not a UUID")
+}
+
+func TestUUIDMismatches(t *testing.T) {
+	wolfID := buildTmeIdentifier("wolf", "Authors")
+	wolfUUID := uuid.NewMD5(uuid.UUID{}, []byte(wolfID)).String()
+	legacy := UUIDStrategy{Legacy: map[string]string{wolfID: "0f07d468-fc37-3c44-bf19-a81f2aae9f36"}}
+	authors := []author{
+		{UUID: wolfUUID, PrefLabel: "Martin Wolf", AlternativeIdentifiers: alternativeIdentifiers{TME: []string{wolfID}}},
+		{UUID: "0f07d468-fc37-3c44-bf19-a81f2aae9f36", PrefLabel: "Nobody"},
+	}
+
+	assert.Equal(t, []uuidMismatch{{UUID: "0f07d468-fc37-3c44-bf19-a81f2aae9f36", PrefLabel: "Nobody"}}, UUIDStrategy{}.uuidMismatches(authors))
+	assert.Equal(t, []uuidMismatch{
+		{UUID: wolfUUID, PrefLabel: "Martin Wolf", TmeIdentifier: wolfID, ExpectedUUID: "0f07d468-fc37-3c44-bf19-a81f2aae9f36"},
+		{UUID: "0f07d468-fc37-3c44-bf19-a81f2aae9f36", PrefLabel: "Nobody"},
+	}, legacy.uuidMismatches(authors))
+}
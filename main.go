@@ -6,6 +6,7 @@ import (
 	"net/http"
 	_ "net/http/pprof"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/Financial-Times/base-ft-rw-app-go/baseftrwapp"
@@ -71,12 +72,96 @@ func main() {
 		Desc:   "Number of requests to be executed in parallel to TME",
 		EnvVar: "BATCH_SIZE",
 	})
+	tmeSource := app.String(cli.StringOpt{
+		Name:   "tme-source",
+		Value:  "",
+		Desc:   "Where the TME taxonomies are read from instead of TME, e.g. file:///path/to/taxonomies for a taxonomy XML file or a directory of them",
+		EnvVar: "TME_SOURCE",
+	})
+	recordDir := app.String(cli.StringOpt{
+		Name:   "record-dir",
+		Value:  "",
+		Desc:   "Directory where every raw TME page and Bertha payload fetched during a reload is archived, if any",
+		EnvVar: "RECORD_DIR",
+	})
+	replayArchive := app.String(cli.StringOpt{
+		Name:   "replay-archive",
+		Value:  "",
+		Desc:   "Archive written with record-dir whose TME pages and Bertha payload are reloaded instead of fetching them, if any",
+		EnvVar: "REPLAY_ARCHIVE",
+	})
+	sourceEndpoint := app.Bool(cli.BoolOpt{
+		Name:   "source-endpoint",
+		Value:  false,
+		Desc:   "Whether to expose the raw TME terms and Bertha row of each author at /transformers/authors/{uuid}/__source. For admins only",
+		EnvVar: "SOURCE_ENDPOINT",
+	})
+	tmeTaxonomies := app.Strings(cli.StringsOpt{
+		Name:   "tme-taxonomies",
+		Value:  []string{"Authors"},
+		Desc:   "TME taxonomies the authors are read from (e.g. Authors, Columnists, Contributors). An author found in several taxonomies is merged into the author of the first one",
+		EnvVar: "TME_TAXONOMIES",
+	})
+	tmePageRetries := app.Int(cli.IntOpt{
+		Name:   "tme-page-retries",
+		Value:  3,
+		Desc:   "How many times a page of TME terms is fetched again before it fails",
+		EnvVar: "TME_PAGE_RETRIES",
+	})
+	tmePageBackoff := app.String(cli.StringOpt{
+		Name:   "tme-page-backoff",
+		Value:  "2s",
+		Desc:   "Wait before the first retry of a page of TME terms (e.g. 500ms, 2s), doubled before each next one",
+		EnvVar: "TME_PAGE_BACKOFF",
+	})
+	tmeMaxFailedPages := app.Int(cli.IntOpt{
+		Name:   "tme-max-failed-pages",
+		Value:  0,
+		Desc:   "How many pages of TME terms can fail after their retries before the whole load fails. The failed pages are listed in the reload report and can be retried with POST /transformers/authors/__reload/failed",
+		EnvVar: "TME_MAX_FAILED_PAGES",
+	})
+	reloadMaxDropPercent := app.Int(cli.IntOpt{
+		Name:   "reload-max-drop-percent",
+		Value:  20,
+		Desc:   "How much fewer authors than the authors being served, in percent, a reload can have before it is blocked instead of activated. 0 turns the check off",
+		EnvVar: "RELOAD_MAX_DROP_PERCENT",
+	})
+	reloadMinCuratedMatches := app.Int(cli.IntOpt{
+		Name:   "reload-min-curated-matches",
+		Value:  0,
+		Desc:   "How many curated authors have to match a TME author for a reload to be activated",
+		EnvVar: "RELOAD_MIN_CURATED_MATCHES",
+	})
+	keptGenerations := app.Int(cli.IntOpt{
+		Name:   "kept-generations",
+		Value:  7,
+		Desc:   "How many generations of authors are kept in the cache after they stop being served, to diff them with /transformers/authors/__diff",
+		EnvVar: "KEPT_GENERATIONS",
+	})
+	seedSnapshot := app.String(cli.StringOpt{
+		Name:   "seed-snapshot",
+		Value:  "",
+		Desc:   "File or URL of a snapshot of the authors, e.g. the /transformers/authors/__snapshot of a running instance, served until the first reload finishes",
+		EnvVar: "SEED_SNAPSHOT",
+	})
+	maxCacheAge := app.String(cli.StringOpt{
+		Name:   "max-cache-age",
+		Value:  "24h",
+		Desc:   "How long ago the authors of the cache file can have been loaded to be served on startup, while they are reloaded in the background (e.g. 12h). 0 waits for the reload",
+		EnvVar: "MAX_CACHE_AGE",
+	})
 	cacheFileName := app.String(cli.StringOpt{
 		Name:   "cache-file-name",
 		Value:  "cache.db",
 		Desc:   "Cache file name",
 		EnvVar: "CACHE_FILE_NAME",
 	})
+	storeKind := app.String(cli.StringOpt{
+		Name:   "store",
+		Value:  authors.BoltStore,
+		Desc:   "Where the authors are stored: bolt, in the cache file, or memory, which loads them again on each start",
+		EnvVar: "STORE",
+	})
 	graphiteTCPAddress := app.String(cli.StringOpt{
 		Name:   "graphiteTCPAddress",
 		Value:  "",
@@ -100,37 +185,153 @@ func main() {
 		Desc:   "The URL of the Bertha Authors JSON source",
 		EnvVar: "BERTHA_SOURCE_URL",
 	})
-
-	tmeTaxonomyName := "Authors"
+	mergePolicyFile := app.String(cli.StringOpt{
+		Name:   "merge-policy-file",
+		Value:  "",
+		Desc:   "JSON file giving the merge strategy (prefer-curated, prefer-tme, curated-if-non-empty or union) per author field. Curated values win by default",
+		EnvVar: "MERGE_POLICY_FILE",
+	})
+	aliasInitials := app.Bool(cli.BoolOpt{
+		Name:   "alias-initials",
+		Value:  false,
+		Desc:   "Whether to add the name with the given names shortened to initials (e.g. M. Wolf) to the aliases",
+		EnvVar: "ALIAS_INITIALS",
+	})
+	aliasStripDiacritics := app.Bool(cli.BoolOpt{
+		Name:   "alias-strip-diacritics",
+		Value:  false,
+		Desc:   "Whether to add the aliases without accents (e.g. Jose Lopez for José López) to the aliases",
+		EnvVar: "ALIAS_STRIP_DIACRITICS",
+	})
+	concordanceFile := app.String(cli.StringOpt{
+		Name:   "concordance-file",
+		Value:  "",
+		Desc:   "JSON file giving the TME identifiers of duplicate authors keyed by the TME identifier of the author they are merged into",
+		EnvVar: "CONCORDANCE_FILE",
+	})
+	uuidNamespace := app.String(cli.StringOpt{
+		Name:   "uuid-namespace",
+		Value:  "",
+		Desc:   "Namespace of the v5 UUIDs of authors. A namespace derived from http://api.ft.com/things/authors is used by default",
+		EnvVar: "UUID_NAMESPACE",
+	})
+	uuidV5Taxonomies := app.Strings(cli.StringsOpt{
+		Name:   "uuid-v5-taxonomies",
+		Value:  []string{},
+		Desc:   "TME taxonomies whose authors get v5 UUIDs instead of MD5 UUIDs",
+		EnvVar: "UUID_V5_TAXONOMIES",
+	})
+	legacyUUIDsFile := app.String(cli.StringOpt{
+		Name:   "legacy-uuids-file",
+		Value:  "",
+		Desc:   "JSON file giving UUIDs which have to be kept, keyed by TME identifier",
+		EnvVar: "LEGACY_UUIDS_FILE",
+	})
+	imageHosts := app.Strings(cli.StringsOpt{
+		Name:   "image-hosts",
+		Value:  authors.DefaultImageHosts,
+		Desc:   "Hosts the images of curated authors can be on",
+		EnvVar: "IMAGE_HOSTS",
+	})
+	origamiImages := app.Bool(cli.BoolOpt{
+		Name:   "origami-images",
+		Value:  false,
+		Desc:   "Whether to rewrite the images of curated authors into Origami image service URLs. Origami image references (e.g. fthead:martin-wolf) are only accepted when set",
+		EnvVar: "ORIGAMI_IMAGES",
+	})
+	origamiImageWidth := app.Int(cli.IntOpt{
+		Name:   "origami-image-width",
+		Value:  0,
+		Desc:   "Width in pixels of the Origami images, if any",
+		EnvVar: "ORIGAMI_IMAGE_WIDTH",
+	})
+	origamiImageFormat := app.String(cli.StringOpt{
+		Name:   "origami-image-format",
+		Value:  "",
+		Desc:   "Format of the Origami images (e.g. auto, jpg or png), if any",
+		EnvVar: "ORIGAMI_IMAGE_FORMAT",
+	})
 
 	app.Action = func() {
 		baseftrwapp.OutputMetricsIfRequired(*graphiteTCPAddress, *graphitePrefix, *logMetrics)
-		client := getResilientClient()
+		var client httpClient = getResilientClient()
+		switch {
+		case *replayArchive != "":
+			replayClient, err := authors.NewReplayClient(*replayArchive)
+			if err != nil {
+				log.Fatalf("Error loading replay archive: %v", err)
+			}
+			client = replayClient
+		case *recordDir != "":
+			client = authors.NewRecordingClient(client, *recordDir)
+		}
+		mergePolicy, err := authors.LoadMergePolicy(*mergePolicyFile)
+		if err != nil {
+			log.Fatalf("Error loading merge policy: %v", err)
+		}
+		concordance, err := authors.LoadConcordance(*concordanceFile)
+		if err != nil {
+			log.Fatalf("Error loading concordance: %v", err)
+		}
+		uuids, err := authors.LoadUUIDStrategy(*uuidNamespace, *uuidV5Taxonomies, *legacyUUIDsFile)
+		if err != nil {
+			log.Fatalf("Error loading UUID strategy: %v", err)
+		}
+		pageBackoff, err := time.ParseDuration(*tmePageBackoff)
+		if err != nil {
+			log.Fatalf("Error parsing TME page backoff: %v", err)
+		}
+		cacheAge, err := time.ParseDuration(*maxCacheAge)
+		if err != nil {
+			log.Fatalf("Error parsing max cache age: %v", err)
+		}
 		modelTransformer := new(authors.AuthorTransformer)
-		s := authors.NewAuthorService(
-			tmereader.NewTmeRepository(
-				client,
-				*tmeBaseURL,
-				*username,
-				*password,
-				*token,
-				*maxRecords,
-				*batchSize,
-				tmeTaxonomyName,
-				&tmereader.AuthorityFiles{},
-				modelTransformer),
-			*baseURL,
-			tmeTaxonomyName,
-			*maxRecords,
-			*cacheFileName,
-			*berthaSrcURL,
-			client)
+		var taxonomies []authors.Taxonomy
+		for _, taxonomyName := range *tmeTaxonomies {
+			var repository tmereader.Repository
+			switch {
+			case *tmeSource == "":
+				repository = tmereader.NewTmeRepository(
+					client,
+					*tmeBaseURL,
+					*username,
+					*password,
+					*token,
+					*maxRecords,
+					*batchSize,
+					taxonomyName,
+					&tmereader.AuthorityFiles{},
+					modelTransformer)
+			case strings.HasPrefix(*tmeSource, authors.FileSourcePrefix):
+				repository = authors.NewFileRepository(strings.TrimPrefix(*tmeSource, authors.FileSourcePrefix), taxonomyName, *maxRecords, modelTransformer)
+			default:
+				log.Fatalf("Unsupported TME source %v", *tmeSource)
+			}
+			taxonomies = append(taxonomies, authors.Taxonomy{Name: taxonomyName, Repository: repository})
+		}
+		store, err := authors.NewAuthorStore(*storeKind, *cacheFileName)
+		if err != nil {
+			log.Fatalf("Error creating the author store: %v", err)
+		}
+		s := authors.NewAuthorService(taxonomies, store, client, authors.ServiceOptions{
+			BaseURL:         *baseURL,
+			MaxTmeRecords:   *maxRecords,
+			BerthaURL:       *berthaSrcURL,
+			MergePolicy:     mergePolicy,
+			Aliases:         authors.AliasOptions{Initials: *aliasInitials, StripDiacritics: *aliasStripDiacritics},
+			Concordance:     concordance,
+			Images:          authors.ImageOptions{AllowedHosts: *imageHosts, Origami: *origamiImages, Width: *origamiImageWidth, Format: *origamiImageFormat},
+			UUIDs:           uuids,
+			Paging:          authors.PagingOptions{Retries: *tmePageRetries, Backoff: pageBackoff, MaxFailedPages: *tmeMaxFailedPages},
+			Guard:           authors.ReloadGuard{MaxDropPercent: *reloadMaxDropPercent, MinCuratedMatches: *reloadMinCuratedMatches},
+			KeptGenerations: *keptGenerations,
+			Startup:         authors.StartupOptions{SeedSnapshot: *seedSnapshot, MaxCacheAge: cacheAge}})
 		defer s.Shutdown()
 		handler := authors.NewAuthorHandler(s)
-		router(handler)
+		router(handler, *sourceEndpoint)
 
 		log.Printf("listening on %d", *port)
-		err := http.ListenAndServe(fmt.Sprintf(":%d", *port), nil)
+		err = http.ListenAndServe(fmt.Sprintf(":%d", *port), nil)
 		if err != nil {
 			log.Errorf("Error by listen and serve: %v", err.Error())
 		}
@@ -138,14 +339,32 @@ func main() {
 	app.Run(os.Args)
 }
 
-func router(handler authors.AuthorHandler) {
+func router(handler authors.AuthorHandler, sourceEndpoint bool) {
 	servicesRouter := mux.NewRouter()
 
 	servicesRouter.HandleFunc("/transformers/authors", handler.GetAuthors).Methods("GET")
 	servicesRouter.HandleFunc("/transformers/authors/__count", handler.GetCount).Methods("GET")
 	servicesRouter.HandleFunc("/transformers/authors/__ids", handler.GetAuthorUUIDs).Methods("GET")
 	servicesRouter.HandleFunc("/transformers/authors/__reload", handler.Reload).Methods("POST")
+	servicesRouter.HandleFunc("/transformers/authors/__reload/failed", handler.RetryFailedPages).Methods("POST")
+	servicesRouter.HandleFunc("/transformers/authors/__reload/activate", handler.ActivateBlockedReload).Methods("POST")
+	servicesRouter.HandleFunc("/transformers/authors/__reload/diff", handler.GetDryRunDiff).Methods("GET")
+	servicesRouter.HandleFunc("/transformers/authors/__generations", handler.GetGenerations).Methods("GET")
+	servicesRouter.HandleFunc("/transformers/authors/__diff", handler.GetDiff).Methods("GET")
+	servicesRouter.HandleFunc("/transformers/authors/__snapshot", handler.GetSnapshot).Methods("GET")
+	servicesRouter.HandleFunc("/transformers/authors/__unmatched", handler.GetUnmatchedCuratedAuthors).Methods("GET")
+	servicesRouter.HandleFunc("/transformers/authors/__duplicates", handler.GetDuplicates).Methods("GET")
+	servicesRouter.HandleFunc("/transformers/authors/__report", handler.GetReloadReport).Methods("GET")
+	servicesRouter.HandleFunc("/transformers/authors/__uuids/check", handler.GetUUIDMismatches).Methods("GET")
+	servicesRouter.HandleFunc("/transformers/authors/__overrides", handler.GetOverrides).Methods("GET")
 	servicesRouter.HandleFunc("/transformers/authors/{uuid:([0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})}", handler.GetAuthorByUUID).Methods("GET")
+	servicesRouter.HandleFunc("/transformers/authors/{uuid:([0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})}/__provenance", handler.GetAuthorProvenance).Methods("GET")
+	if sourceEndpoint {
+		servicesRouter.HandleFunc("/transformers/authors/{uuid:([0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})}/__source", handler.GetAuthorSource).Methods("GET")
+	}
+	servicesRouter.HandleFunc("/transformers/authors/{uuid:([0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})}/__override", handler.PutOverride).Methods("PUT")
+	servicesRouter.HandleFunc("/transformers/authors/{uuid:([0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})}/__override", handler.PatchOverride).Methods("PATCH")
+	servicesRouter.HandleFunc("/transformers/authors/{uuid:([0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})}/__override", handler.DeleteOverride).Methods("DELETE")
 
 	var monitoringRouter http.Handler = servicesRouter
 	monitoringRouter = httphandlers.TransactionAwareRequestLoggingHandler(log.StandardLogger(), monitoringRouter)
@@ -155,7 +374,7 @@ func router(handler authors.AuthorHandler) {
 			SystemCode:  "v1-authors-tf",
 			Name:        "V1 Authors Transformer",
 			Description: "It pulls and transforms V1/TME Authors into the UPP JSON model of an Author.",
-			Checks:      []fthealth.Check{handler.HealthCheck()},
+			Checks:      []fthealth.Check{handler.HealthCheck(), handler.UnmatchedCuratedAuthorsCheck(), handler.BlockedReloadCheck(), handler.CacheCompactionCheck()},
 		},
 		Timeout: 10 * time.Second,
 	}
@@ -169,6 +388,10 @@ func router(handler authors.AuthorHandler) {
 	http.Handle("/", monitoringRouter)
 }
 
+type httpClient interface {
+	Do(req *http.Request) (resp *http.Response, err error)
+}
+
 func getResilientClient() *pester.Client {
 	tr := &http.Transport{
 		MaxIdleConnsPerHost: 32,